@@ -7,7 +7,12 @@
 
 package main
 
-import "github.com/tmthrgd/asm"
+import (
+	"bytes"
+	"math/bits"
+
+	"github.com/tmthrgd/asm"
+)
 
 const header = `// Copyright 2017 Tom Thorogood. All rights reserved.
 // Use of this source code is governed by a
@@ -16,261 +21,1796 @@ const header = `// Copyright 2017 Tom Thorogood. All rights reserved.
 //
 // This file is auto-generated - do not modify
 
-// +build amd64,!gccgo,!appengine
-`
+// +build amd64,!gccgo,!appengine,!purego
+`
+
+// nonTemporalThreshold is the length at and above which the kernels
+// use non-temporal stores. Buffers this large are unlikely to remain in
+// the cache, so bypassing it avoids evicting other data and the
+// read-for-ownership of each destination cache line.
+const nonTemporalThreshold = 4 * 1024 * 1024
+
+// vector describes the registers and instructions of a SIMD extension
+// used by bulkASM.
+type vector struct {
+	suffix string
+	width  int
+	regs   [16]asm.Register
+
+	movu, mova, movnt func(*asm.Asm, ...asm.Operand)
+}
+
+var (
+	sse2 = vector{
+		suffix: "ASM",
+		width:  16,
+		regs: [...]asm.Register{
+			asm.X0, asm.X1, asm.X2, asm.X3, asm.X4, asm.X5, asm.X6, asm.X7,
+			asm.X8, asm.X9, asm.X10, asm.X11, asm.X12, asm.X13, asm.X14, asm.X15,
+		},
+		movu:  (*asm.Asm).Movou,
+		mova:  (*asm.Asm).Movo,
+		movnt: (*asm.Asm).Movnto,
+	}
+	avx2 = vector{
+		suffix: "AVX2",
+		width:  32,
+		regs: [...]asm.Register{
+			asm.Y0, asm.Y1, asm.Y2, asm.Y3, asm.Y4, asm.Y5, asm.Y6, asm.Y7,
+			asm.Y8, asm.Y9, asm.Y10, asm.Y11, asm.Y12, asm.Y13, asm.Y14, asm.Y15,
+		},
+		movu:  (*asm.Asm).Vmovdqu,
+		mova:  (*asm.Asm).Vmovdqa,
+		movnt: (*asm.Asm).Vmovntdq,
+	}
+	avx512 = vector{
+		suffix: "AVX512",
+		width:  64,
+		regs: [...]asm.Register{
+			asm.Z0, asm.Z1, asm.Z2, asm.Z3, asm.Z4, asm.Z5, asm.Z6, asm.Z7,
+			asm.Z8, asm.Z9, asm.Z10, asm.Z11, asm.Z12, asm.Z13, asm.Z14, asm.Z15,
+		},
+		movu:  (*asm.Asm).Vmovdqu64,
+		mova:  (*asm.Asm).Vmovdqa64,
+		movnt: (*asm.Asm).Vmovntdq,
+	}
+)
+
+// logicOp describes a bitwise operation for bulkASM.
+type logicOp struct {
+	// name is the prefix of the generated function names.
+	name string
+
+	// sse and vex set x to the operation, without any negation,
+	// applied to y, the first source, and x, the second source. They
+	// are nil for Not.
+	sse func(a *asm.Asm, x, y asm.Operand)
+	vex func(a *asm.Asm, x, y asm.Operand)
+
+	// negate is set if the result of sse or vex must be inverted.
+	negate bool
+
+	// opb is the equivalent operation on a single byte in AX.
+	opb func(a *asm.Asm, ops ...asm.Operand)
+
+	// fn is the operation on the first and second sources, it is
+	// used to compute the VPTERNLOGD truth table.
+	fn func(a, b uint8) uint8
+}
+
+// bulkASM generates the kernel for op using the instructions of v.
+//
+// Buffers are processed from the end backwards, with the first and
+// last vectors handled by two overlapping unaligned vectors so that
+// every other store is aligned. Only the SSE2 kernels handle buffers
+// shorter than a vector, using overlapping 8 or 4 byte operations, the
+// others must only be called with len >= v.width.
+func bulkASM(a *asm.Asm, op logicOp, v vector) {
+	a.NewFunction(op.name + v.suffix)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+
+	srcs := []string{"a", "b"}
+	if op.sse == nil {
+		srcs = []string{"src"}
+	}
+
+	srcRegs := []asm.Register{asm.SI, asm.DX}[:len(srcs)]
+	srcArgs := make([]asm.Operand, len(srcs))
+	for i, name := range srcs {
+		srcArgs[i] = a.Argument(name, 8)
+	}
+
+	length := a.Argument("len", 8)
+
+	a.Start()
+
+	hugeloop := a.NewLabel("hugeloop")
+	big := a.NewLabel("big")
+	bigloop := a.NewLabel("bigloop")
+	done := a.NewLabel("done")
+	ntloop := a.NewLabel("ntloop")
+	small := a.NewLabel("small")
+	word := a.NewLabel("word")
+	loop := a.NewLabel("loop")
+	ret := a.NewLabel("ret")
+
+	di, cx, end := asm.DI, asm.BX, asm.R8
+	width, ones := v.width, v.regs[15]
+
+	a.Movq(di, dst)
+	for i, r := range srcRegs {
+		a.Movq(r, srcArgs[i])
+	}
+	a.Movq(cx, length)
+
+	// apply sets x to op applied to y, the first source, and x.
+	apply := func(x, y asm.Operand) {
+		switch {
+		case v.width == 64:
+			// The truth table is indexed by the bits of
+			// x, y and y.
+			imm := op.fn(0xcc, 0xf0)
+			if len(srcRegs) == 1 {
+				y = x
+			}
+
+			a.Vpternlogd(x, y, y, asm.Constant(imm))
+		case v.width == 32:
+			if op.vex != nil {
+				op.vex(a, x, y)
+			}
+
+			if op.negate {
+				a.Vpxor(x, x, ones)
+			}
+		default:
+			if op.sse != nil {
+				op.sse(a, x, y)
+			}
+
+			if op.negate {
+				a.Pxor(x, ones)
+			}
+		}
+	}
+
+	// calc loads each source from addr into x, and y with two
+	// sources, using mov and applies op.
+	calc := func(mov func(*asm.Asm, ...asm.Operand), x, y asm.Register, addr func(base asm.Register) asm.Operand) {
+		if len(srcRegs) == 2 {
+			mov(a, y, addr(srcRegs[0]))
+		}
+
+		mov(a, x, addr(srcRegs[len(srcRegs)-1]))
+		apply(x, y)
+	}
+
+	at := func(off int) func(base asm.Register) asm.Operand {
+		return func(base asm.Register) asm.Operand {
+			return asm.Address(base, cx, asm.SX1, off)
+		}
+	}
+
+	start := func(base asm.Register) asm.Operand {
+		return asm.Address(base)
+	}
+
+	hugeblock := func(store func(*asm.Asm, ...asm.Operand)) {
+		for i := 0; i < 4; i++ {
+			calc(v.movu, v.regs[2*i+1], v.regs[2*i], at(-(i+1)*width))
+		}
+
+		for i := 0; i < 4; i++ {
+			store(a, asm.Address(di, cx, asm.SX1, -(i+1)*width), v.regs[2*i+1])
+		}
+	}
+
+	if op.negate && v.width != 64 {
+		if v.width == 32 {
+			a.Vpcmpeqd(ones, ones, ones)
+		} else {
+			a.Pcmpeql(ones, ones)
+		}
+	}
+
+	if v.width == 16 {
+		a.Cmpq(asm.Constant(16), cx)
+		a.Jb(small)
+	}
+
+	// The first and last vectors are computed before anything is
+	// stored, as dst may be the same as one of the sources.
+	a.Leaq(end, asm.Address(di, cx, asm.SX1, 0))
+	calc(v.movu, v.regs[9], v.regs[8], start)
+	calc(v.movu, v.regs[11], v.regs[10], at(-width))
+
+	// Align the end of dst to the vector width, the unaligned bytes
+	// are covered by the last vector.
+	a.Movq(asm.CX, end)
+	a.Andq(asm.CX, asm.Constant(width-1))
+	a.Subq(cx, asm.CX)
+
+	// Non-temporal stores avoid polluting the cache with buffers
+	// that are unlikely to remain in it.
+	a.Cmpq(asm.Constant(nonTemporalThreshold), cx)
+	a.Jae(ntloop)
+
+	a.Cmpq(asm.Constant(4*width), cx)
+	a.Jb(big)
+
+	a.Label(hugeloop)
+
+	hugeblock(v.mova)
+
+	a.Subq(cx, asm.Constant(4*width))
+
+	a.Cmpq(asm.Constant(4*width), cx)
+	a.Jae(hugeloop)
+
+	a.Label(big)
+
+	a.Cmpq(asm.Constant(width), cx)
+	a.Jb(done)
+
+	a.Label(bigloop)
+
+	calc(v.movu, v.regs[1], v.regs[0], at(-width))
+	v.mova(a, asm.Address(di, cx, asm.SX1, -width), v.regs[1])
+
+	a.Subq(cx, asm.Constant(width))
+
+	a.Cmpq(asm.Constant(width), cx)
+	a.Jae(bigloop)
+
+	a.Label(done)
+
+	v.movu(a, asm.Address(end, -width), v.regs[11])
+	v.movu(a, asm.Address(di), v.regs[9])
+
+	if v.width != 16 {
+		a.Vzeroupper()
+	}
+
+	a.Ret()
+
+	a.Label(ntloop)
+
+	hugeblock(v.movnt)
+
+	a.Subq(cx, asm.Constant(4*width))
+
+	a.Cmpq(asm.Constant(4*width), cx)
+	a.Jae(ntloop)
+
+	a.Sfence()
+
+	a.Jmp(big)
+
+	if v.width != 16 {
+		return
+	}
+
+	a.Label(small)
+
+	a.Cmpq(asm.Constant(8), cx)
+	a.Jb(word)
+
+	calc((*asm.Asm).Movq, asm.X9, asm.X8, start)
+	calc((*asm.Asm).Movq, asm.X11, asm.X10, at(-8))
+
+	a.Movq(asm.Address(di), asm.X9)
+	a.Movq(asm.Address(di, cx, asm.SX1, -8), asm.X11)
+
+	a.Ret()
+
+	a.Label(word)
+
+	a.Cmpq(asm.Constant(4), cx)
+	a.Jb(loop.Suffix("check"))
+
+	calc((*asm.Asm).Movl, asm.X9, asm.X8, start)
+	calc((*asm.Asm).Movl, asm.X11, asm.X10, at(-4))
+
+	a.Movl(asm.Address(di), asm.X9)
+	a.Movl(asm.Address(di, cx, asm.SX1, -4), asm.X11)
+
+	a.Ret()
+
+	a.Label(loop.Suffix("check"))
+
+	a.Testq(cx, cx)
+	a.Jz(ret)
+
+	a.Label(loop)
+
+	a.Movb(asm.AX, asm.Address(srcRegs[0], cx, asm.SX1, -1))
+	if len(srcRegs) == 1 {
+		op.opb(a, asm.AX)
+	} else {
+		op.opb(a, asm.AX, asm.Address(srcRegs[1], cx, asm.SX1, -1))
+	}
+	a.Movb(asm.Address(di, cx, asm.SX1, -1), asm.AX)
+
+	a.Subq(cx, asm.Constant(1))
+	a.Jnz(loop)
+
+	a.Label(ret)
+
+	a.Ret()
+}
+
+// logicASM returns a function that generates the SSE2, AVX2 and
+// AVX-512 kernels for op.
+func logicASM(op logicOp) func(a *asm.Asm) {
+	return func(a *asm.Asm) {
+		bulkASM(a, op, sse2)
+		bulkASM(a, op, avx2)
+		bulkASM(a, op, avx512)
+	}
+}
+
+// negateByte wraps the byte operation opb so that its result is
+// inverted.
+func negateByte(opb func(a *asm.Asm, ops ...asm.Operand)) func(a *asm.Asm, ops ...asm.Operand) {
+	return func(a *asm.Asm, ops ...asm.Operand) {
+		if len(ops) != 2 {
+			panic("wrong number of operands")
+		}
+
+		opb(a, ops...)
+		a.Notb(ops[0])
+	}
+}
+
+func sse(op func(*asm.Asm, ...asm.Operand)) func(a *asm.Asm, x, y asm.Operand) {
+	return func(a *asm.Asm, x, y asm.Operand) {
+		op(a, x, y)
+	}
+}
+
+func vex(op func(*asm.Asm, ...asm.Operand)) func(a *asm.Asm, x, y asm.Operand) {
+	return func(a *asm.Asm, x, y asm.Operand) {
+		op(a, x, x, y)
+	}
+}
+
+var (
+	xorASM = logicASM(logicOp{
+		name: "xor",
+		sse:  sse((*asm.Asm).Pxor),
+		vex:  vex((*asm.Asm).Vpxor),
+		opb:  (*asm.Asm).Xorb,
+		fn:   func(a, b uint8) uint8 { return a ^ b },
+	})
+	xnorASM = logicASM(logicOp{
+		name:   "xnor",
+		sse:    sse((*asm.Asm).Pxor),
+		vex:    vex((*asm.Asm).Vpxor),
+		negate: true,
+		opb:    negateByte((*asm.Asm).Xorb),
+		fn:     func(a, b uint8) uint8 { return ^(a ^ b) },
+	})
+	andASM = logicASM(logicOp{
+		name: "and",
+		sse:  sse((*asm.Asm).Pand),
+		vex:  vex((*asm.Asm).Vpand),
+		opb:  (*asm.Asm).Andb,
+		fn:   func(a, b uint8) uint8 { return a & b },
+	})
+	andNotASM = logicASM(logicOp{
+		name: "andNot",
+		sse:  sse((*asm.Asm).Pandn),
+		vex:  vex((*asm.Asm).Vpandn),
+		opb: func(a *asm.Asm, ops ...asm.Operand) {
+			if len(ops) != 2 {
+				panic("wrong number of operands")
+			}
+
+			a.Movb(asm.R15, ops[1])
+			a.Notb(asm.R15)
+			a.Andb(ops[0], asm.R15)
+		},
+		fn: func(a, b uint8) uint8 { return a &^ b },
+	})
+	nandASM = logicASM(logicOp{
+		name:   "nand",
+		sse:    sse((*asm.Asm).Pand),
+		vex:    vex((*asm.Asm).Vpand),
+		negate: true,
+		opb:    negateByte((*asm.Asm).Andb),
+		fn:     func(a, b uint8) uint8 { return ^(a & b) },
+	})
+	orASM = logicASM(logicOp{
+		name: "or",
+		sse:  sse((*asm.Asm).Por),
+		vex:  vex((*asm.Asm).Vpor),
+		opb:  (*asm.Asm).Orb,
+		fn:   func(a, b uint8) uint8 { return a | b },
+	})
+	norASM = logicASM(logicOp{
+		name:   "nor",
+		sse:    sse((*asm.Asm).Por),
+		vex:    vex((*asm.Asm).Vpor),
+		negate: true,
+		opb:    negateByte((*asm.Asm).Orb),
+		fn:     func(a, b uint8) uint8 { return ^(a | b) },
+	})
+	notASM = logicASM(logicOp{
+		name:   "not",
+		negate: true,
+		opb:    (*asm.Asm).Notb,
+		fn:     func(a, b uint8) uint8 { return ^b },
+	})
+)
+
+func galoisMulXORASM(a *asm.Asm) {
+	mask := a.Data("galoisMask", bytes.Repeat([]byte{0x0f}, 16))
+
+	a.NewFunction("galoisMulXORASM")
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	length := a.Argument("len", 8)
+	tables := a.Argument("tables", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	di, si, cx, tbl := asm.DI, asm.SI, asm.BX, asm.DX
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(cx, length)
+	a.Movq(tbl, tables)
+
+	a.Movou(asm.X13, mask)
+	a.Movou(asm.X14, asm.Address(tbl))
+	a.Movou(asm.X15, asm.Address(tbl, 16))
+
+	a.Label(loop)
+
+	a.Movou(asm.X0, asm.Address(si, cx, asm.SX1, -16))
+	a.Movou(asm.X1, asm.X0)
+
+	a.Psrlw(asm.X1, asm.Constant(4))
+	a.Pand(asm.X0, asm.X13)
+	a.Pand(asm.X1, asm.X13)
+
+	a.Movou(asm.X2, asm.X14)
+	a.Movou(asm.X3, asm.X15)
+
+	a.Pshufb(asm.X2, asm.X0)
+	a.Pshufb(asm.X3, asm.X1)
+
+	a.Movou(asm.X4, asm.Address(di, cx, asm.SX1, -16))
+
+	a.Pxor(asm.X2, asm.X3)
+	a.Pxor(asm.X2, asm.X4)
+
+	a.Movou(asm.Address(di, cx, asm.SX1, -16), asm.X2)
+
+	a.Subq(cx, asm.Constant(16))
+	a.Jnz(loop)
+
+	a.Ret()
+}
+
+// popcnt counts the bits set in each quadword of Y0 using the nibble
+// lookup table in lut and the 0x0f mask in mask, the result is added to
+// the quadwords of acc. Y1 is clobbered and zero must hold zero.
+func popcnt(a *asm.Asm, acc, lut, mask, zero asm.Operand) {
+	a.Vpand(asm.Y1, asm.Y0, mask)
+	a.Vpsrlw(asm.Y0, asm.Y0, asm.Constant(4))
+	a.Vpand(asm.Y0, asm.Y0, mask)
+
+	a.Vpshufb(asm.Y1, lut, asm.Y1)
+	a.Vpshufb(asm.Y0, lut, asm.Y0)
+	a.Vpaddb(asm.Y0, asm.Y0, asm.Y1)
+
+	a.Vpsadbw(asm.Y0, asm.Y0, zero)
+	a.Vpaddq(acc, acc, asm.Y0)
+}
+
+// sumQuadwords sets dst to the sum of the four quadwords in the Y
+// register accY, acc must be the X register that aliases accY. X1 is
+// clobbered.
+func sumQuadwords(a *asm.Asm, dst, acc, accY asm.Operand) {
+	a.Vextracti128(asm.X1, accY, asm.Constant(1))
+	a.Vpaddq(acc, acc, asm.X1)
+	a.Vpshufd(asm.X1, acc, asm.Constant(0x4e))
+	a.Vpaddq(acc, acc, asm.X1)
+	a.Vmovq(dst, acc)
+}
+
+func popcntData(a *asm.Asm) (lut, mask asm.Data) {
+	var nibbles []byte
+	for i := 0; i < 32; i++ {
+		nibbles = append(nibbles, byte(bits.OnesCount8(uint8(i&0x0f))))
+	}
+
+	lut = a.Data("popcntLUT", nibbles)
+	mask = a.Data("popcntMask", bytes.Repeat([]byte{0x0f}, 32))
+	return
+}
+
+func hammingASM(a *asm.Asm, name string, width int, lut, mask asm.Data) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	query := a.Argument("query", 8)
+	rows := a.Argument("rows", 8)
+	rowSize := a.Argument("rowSize", 8)
+	nrows := a.Argument("nrows", 8)
+
+	a.Start()
+
+	rowloop := a.NewLabel("rowloop")
+	loop := a.NewLabel("loop")
+
+	di, si, dx, cx, bx, ax := asm.DI, asm.SI, asm.DX, asm.CX, asm.BX, asm.AX
+
+	a.Movq(di, dst)
+	a.Movq(si, query)
+	a.Movq(dx, rows)
+	a.Movq(cx, rowSize)
+	a.Movq(bx, nrows)
+
+	if width == 32 {
+		a.Vmovdqu(asm.Y15, lut)
+		a.Vmovdqu(asm.Y14, mask)
+		a.Vpxor(asm.Y13, asm.Y13, asm.Y13)
+	}
+
+	a.Label(rowloop)
+
+	if width == 32 {
+		a.Vpxor(asm.Y12, asm.Y12, asm.Y12)
+	} else {
+		a.Vpxorq(asm.Z12, asm.Z12, asm.Z12)
+	}
+
+	a.Xorq(ax, ax)
+
+	a.Label(loop)
+
+	if width == 32 {
+		a.Vmovdqu(asm.Y0, asm.Address(si, ax, asm.SX1))
+		a.Vpxor(asm.Y0, asm.Y0, asm.Address(dx, ax, asm.SX1))
+
+		popcnt(a, asm.Y12, asm.Y15, asm.Y14, asm.Y13)
+	} else {
+		a.Vmovdqu64(asm.Z0, asm.Address(si, ax, asm.SX1))
+		a.Vpxorq(asm.Z0, asm.Z0, asm.Address(dx, ax, asm.SX1))
+		a.Vpopcntq(asm.Z0, asm.Z0)
+		a.Vpaddq(asm.Z12, asm.Z12, asm.Z0)
+	}
+
+	a.Addq(ax, asm.Constant(width))
+	a.Cmpq(cx, ax)
+	a.Jb(loop)
+
+	if width == 64 {
+		a.Vextracti64x4(asm.Y1, asm.Z12, asm.Constant(1))
+		a.Vpaddq(asm.Y12, asm.Y12, asm.Y1)
+	}
+
+	sumQuadwords(a, asm.R8, asm.X12, asm.Y12)
+
+	a.Movl(asm.Address(di), asm.R8)
+
+	a.Addq(di, asm.Constant(4))
+	a.Addq(dx, cx)
+
+	a.Subq(bx, asm.Constant(1))
+	a.Jnz(rowloop)
+
+	a.Vzeroupper()
+	a.Ret()
+}
+
+func onesCountASM(a *asm.Asm, name string, width int, lut, mask asm.Data) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	src := a.Argument("src", 8)
+	length := a.Argument("len", 8)
+	ret := a.Argument("n", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	si, bx, ax := asm.SI, asm.BX, asm.AX
+
+	a.Movq(si, src)
+	a.Movq(bx, length)
+
+	if width == 32 {
+		a.Vmovdqu(asm.Y15, lut)
+		a.Vmovdqu(asm.Y14, mask)
+		a.Vpxor(asm.Y13, asm.Y13, asm.Y13)
+		a.Vpxor(asm.Y12, asm.Y12, asm.Y12)
+	} else {
+		a.Vpxorq(asm.Z12, asm.Z12, asm.Z12)
+	}
+
+	a.Xorq(ax, ax)
+
+	a.Label(loop)
+
+	if width == 32 {
+		a.Vmovdqu(asm.Y0, asm.Address(si, ax, asm.SX1))
+
+		popcnt(a, asm.Y12, asm.Y15, asm.Y14, asm.Y13)
+	} else {
+		a.Vpopcntq(asm.Z0, asm.Address(si, ax, asm.SX1))
+		a.Vpaddq(asm.Z12, asm.Z12, asm.Z0)
+	}
+
+	a.Addq(ax, asm.Constant(width))
+	a.Cmpq(bx, ax)
+	a.Jb(loop)
+
+	if width == 64 {
+		a.Vextracti64x4(asm.Y1, asm.Z12, asm.Constant(1))
+		a.Vpaddq(asm.Y12, asm.Y12, asm.Y1)
+	}
+
+	sumQuadwords(a, asm.R8, asm.X12, asm.Y12)
+
+	a.Movq(ret, asm.R8)
+
+	a.Vzeroupper()
+	a.Ret()
+}
+
+func popcntASM(a *asm.Asm) {
+	lut, mask := popcntData(a)
+
+	hammingASM(a, "hammingAVX2", 32, lut, mask)
+	hammingASM(a, "hammingAVX512", 64, lut, mask)
+
+	onesCountASM(a, "onesCountAVX2", 32, lut, mask)
+	onesCountASM(a, "onesCountAVX512", 64, lut, mask)
+}
+
+// maskOp describes a comparison for maskASM.
+type maskOp struct {
+	// name is the prefix of the generated function names.
+	name string
+
+	// cmp sets the bytes of the register that it returns to 0xff
+	// where the comparison holds for the bytes of x, and to zero
+	// otherwise. val and val2 hold the broadcast comparison values,
+	// bias holds 0x80 in every byte and t is a scratch register.
+	cmp func(a *asm.Asm, v vector, x, val, val2, bias, t asm.Register) asm.Register
+
+	// biased is set if cmp uses bias.
+	biased bool
+
+	// negate is set if the result of cmp must be inverted.
+	negate bool
+
+	// ranged is set if the kernel takes a second comparison value.
+	ranged bool
+}
+
+var maskOps = []maskOp{
+	{
+		name: "equalMask",
+		cmp: func(a *asm.Asm, v vector, x, val, val2, bias, t asm.Register) asm.Register {
+			if v.width == 16 {
+				a.Pcmpeqb(x, val)
+			} else {
+				a.Vpcmpeqb(x, x, val)
+			}
+
+			return x
+		},
+	},
+	{
+		// The comparison value is biased by the caller so that the
+		// signed PCMPGTB compares unsigned bytes.
+		name: "lessMask",
+		cmp: func(a *asm.Asm, v vector, x, val, val2, bias, t asm.Register) asm.Register {
+			if v.width == 16 {
+				a.Pxor(x, bias)
+				a.Movou(t, val)
+				a.Pcmpgtb(t, x)
+			} else {
+				a.Vpxor(x, x, bias)
+				a.Vpcmpgtb(t, val, x)
+			}
+
+			return t
+		},
+		biased: true,
+	},
+	{
+		name: "greaterMask",
+		cmp: func(a *asm.Asm, v vector, x, val, val2, bias, t asm.Register) asm.Register {
+			if v.width == 16 {
+				a.Pxor(x, bias)
+				a.Pcmpgtb(x, val)
+			} else {
+				a.Vpxor(x, x, bias)
+				a.Vpcmpgtb(x, x, val)
+			}
+
+			return x
+		},
+		biased: true,
+	},
+	{
+		// A byte x is in the range [lo, hi] if x-lo <= hi-lo, with
+		// wrapping subtraction. The caller passes the biased hi-lo as
+		// the second value, the kernel finds the bytes that are out of
+		// range and inverts the result.
+		name: "rangeMask",
+		cmp: func(a *asm.Asm, v vector, x, val, val2, bias, t asm.Register) asm.Register {
+			if v.width == 16 {
+				a.Psubb(x, val)
+				a.Pxor(x, bias)
+				a.Pcmpgtb(x, val2)
+			} else {
+				a.Vpsubb(x, x, val)
+				a.Vpxor(x, x, bias)
+				a.Vpcmpgtb(x, x, val2)
+			}
+
+			return x
+		},
+		biased: true,
+		negate: true,
+		ranged: true,
+	},
+}
+
+// broadcastByte sets every byte of r, a register of v, to the low byte
+// of AX.
+func broadcastByte(a *asm.Asm, v vector, r, x asm.Register) {
+	a.Movq(x, asm.AX)
+
+	if v.width == 16 {
+		a.Punpcklbw(x, x)
+		a.Punpcklwl(x, x)
+		a.Pshufl(x, x, asm.Constant(0))
+	} else {
+		a.Vpbroadcastb(r, x)
+	}
+}
+
+// maskASM generates the kernel for op using the instructions of v. Each
+// vector of src sets v.width/8 bytes of dst from the most significant
+// bit of each compared byte. len must be a non-zero multiple of
+// v.width.
+func maskASM(a *asm.Asm, op maskOp, v vector, bias asm.Data) {
+	a.NewFunction(op.name + v.suffix)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	length := a.Argument("len", 8)
+
+	var val, val2 asm.Operand
+	if op.ranged {
+		val = a.Argument("lo", 8)
+		val2 = a.Argument("hi", 8)
+	} else {
+		val = a.Argument("v", 8)
+	}
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	di, si, bx := asm.DI, asm.SI, asm.BX
+	x, t := v.regs[0], v.regs[1]
+	val1R, val2R, biasR := v.regs[15], v.regs[14], v.regs[13]
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(bx, length)
+
+	a.Movq(asm.AX, val)
+	broadcastByte(a, v, val1R, asm.X15)
+
+	if op.ranged {
+		a.Movq(asm.AX, val2)
+		broadcastByte(a, v, val2R, asm.X14)
+	}
+
+	if op.biased {
+		v.movu(a, biasR, bias)
+	}
+
+	a.Label(loop)
+
+	v.movu(a, x, asm.Address(si))
+
+	r := op.cmp(a, v, x, val1R, val2R, biasR, t)
+
+	if v.width == 16 {
+		a.Pmovmskb(asm.AX, r)
+	} else {
+		a.Vpmovmskb(asm.AX, r)
+	}
+
+	if op.negate {
+		a.Notl(asm.AX)
+	}
+
+	if v.width == 16 {
+		a.Movw(asm.Address(di), asm.AX)
+	} else {
+		a.Movl(asm.Address(di), asm.AX)
+	}
+
+	a.Addq(si, asm.Constant(v.width))
+	a.Addq(di, asm.Constant(v.width/8))
+	a.Subq(bx, asm.Constant(v.width))
+	a.Jnz(loop)
+
+	if v.width != 16 {
+		a.Vzeroupper()
+	}
+
+	a.Ret()
+}
+
+func compareMaskASM(a *asm.Asm) {
+	bias := a.Data("maskBias", bytes.Repeat([]byte{0x80}, 32))
+
+	for _, op := range maskOps {
+		maskASM(a, op, sse2, bias)
+		maskASM(a, op, avx2, bias)
+	}
+}
+
+// packASM generates a kernel that sets each bit of dst if the
+// corresponding byte of src is non-zero. len must be a non-zero
+// multiple of 16. If msb is set, the first byte of each group of eight
+// sets the most significant bit, otherwise the least significant.
+func packASM(a *asm.Asm, name string, msb bool, reverse asm.Data) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	length := a.Argument("len", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	di, si, bx := asm.DI, asm.SI, asm.BX
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(bx, length)
+
+	a.Pxor(asm.X15, asm.X15)
+
+	if msb {
+		a.Movou(asm.X14, reverse)
+	}
+
+	a.Label(loop)
+
+	a.Movou(asm.X0, asm.Address(si))
+
+	// PMOVMSKB sets the bits in the order of the bytes, so reversing
+	// the bytes of each group of eight reverses the bits.
+	if msb {
+		a.Pshufb(asm.X0, asm.X14)
+	}
+
+	a.Pcmpeqb(asm.X0, asm.X15)
+	a.Pmovmskb(asm.AX, asm.X0)
+	a.Notl(asm.AX)
+	a.Movw(asm.Address(di), asm.AX)
+
+	a.Addq(si, asm.Constant(16))
+	a.Addq(di, asm.Constant(2))
+	a.Subq(bx, asm.Constant(16))
+	a.Jnz(loop)
+
+	a.Ret()
+}
+
+// unpackASM generates a kernel that sets each byte of dst to one if the
+// corresponding bit of src is set and to zero otherwise. len is the
+// length of dst and must be a non-zero multiple of 16. bits holds the
+// bit of src that corresponds to each byte of dst.
+func unpackASM(a *asm.Asm, name string, spread, bits, one asm.Data) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	length := a.Argument("len", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	di, si, bx := asm.DI, asm.SI, asm.BX
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(bx, length)
+
+	a.Movou(asm.X14, spread)
+	a.Movou(asm.X13, bits)
+	a.Movou(asm.X12, one)
+
+	a.Label(loop)
+
+	bitsToBytes(a, asm.X0, asm.Address(si))
+	a.Pand(asm.X0, asm.X12)
+	a.Movou(asm.Address(di), asm.X0)
+
+	a.Addq(si, asm.Constant(2))
+	a.Addq(di, asm.Constant(16))
+	a.Subq(bx, asm.Constant(16))
+	a.Jnz(loop)
+
+	a.Ret()
+}
+
+// bitsToBytes sets each byte of x to 0xff if the corresponding bit of
+// the two bytes at src is set and to zero otherwise. X14 must hold the
+// spread table and X13 the bit of each byte from bitTables. AX is
+// clobbered.
+func bitsToBytes(a *asm.Asm, x, src asm.Operand) {
+	// The two bytes of src are copied to the low and high halves of
+	// x, then each byte is compared against its bit.
+	a.Movwlzx(asm.AX, src)
+	a.Movd(x, asm.AX)
+	a.Pshufb(x, asm.X14)
+	a.Pand(x, asm.X13)
+	a.Pcmpeqb(x, asm.X13)
+}
+
+// bitTables returns the tables used by bitsToBytes. spread is the
+// PSHUFB shuffle that copies the first byte to the low eight bytes and
+// the second byte to the high eight, lsb and msb hold the bit that
+// corresponds to each byte in the LSBFirst and MSBFirst orders.
+func bitTables() (spread, lsb, msb []byte) {
+	for i := 0; i < 16; i++ {
+		spread = append(spread, byte(i/8))
+		lsb = append(lsb, 1<<uint(i%8))
+		msb = append(msb, 0x80>>uint(i%8))
+	}
+
+	return
+}
+
+func packBitsASM(a *asm.Asm) {
+	var reverse []byte
+	for i := 0; i < 16; i++ {
+		reverse = append(reverse, byte(i&^7|7-i&7))
+	}
+
+	spread, lsb, msb := bitTables()
+
+	reverseData := a.Data("packReverse", reverse)
+
+	packASM(a, "packLSBASM", false, reverseData)
+	packASM(a, "packMSBASM", true, reverseData)
+
+	spreadData := a.Data("unpackSpread", spread)
+	one := a.Data("unpackOne", bytes.Repeat([]byte{1}, 16))
+
+	unpackASM(a, "unpackLSBASM", spreadData, a.Data("unpackLSB", lsb), one)
+	unpackASM(a, "unpackMSBASM", spreadData, a.Data("unpackMSB", msb), one)
+}
+
+// compressASM generates a kernel that copies the bytes of src selected
+// by the bits of mask to dst, or, if expand is set, copies consecutive
+// bytes of src to the bytes of dst selected by mask and zeroes the
+// others.
+//
+// Each block of eight bytes is handled by PSHUFB with a shuffle from
+// the table indexed by its mask byte, every load and store is eight
+// bytes long. The kernel stops after blocks blocks or once fewer than
+// eight bytes of dst (or src if expand is set) remain in limit. It
+// returns the number of bytes written to dst (or read from src) and the
+// number of blocks processed.
+func compressASM(a *asm.Asm, name string, expand bool, shuffle, count asm.Data) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	mask := a.Argument("mask", 8)
+	limit := a.Argument("limit", 8)
+	blocks := a.Argument("blocks", 8)
+	retN := a.Argument("n", 8)
+	retDone := a.Argument("processed", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+	done := a.NewLabel("done")
+
+	di, si, r8, dx, bx := asm.DI, asm.SI, asm.R8, asm.DX, asm.BX
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(r8, mask)
+	a.Movq(dx, limit)
+	a.Movq(bx, blocks)
+
+	a.Leaq(asm.R9, shuffle)
+	a.Leaq(asm.R10, count)
+
+	// R11 is the start of the dense buffer and R12 the number of
+	// blocks.
+	if expand {
+		a.Movq(asm.R11, si)
+	} else {
+		a.Movq(asm.R11, di)
+	}
+
+	a.Movq(asm.R12, bx)
+
+	a.Label(loop)
+
+	a.Cmpq(asm.Constant(8), dx)
+	a.Jb(done)
+
+	a.Movbqzx(asm.AX, asm.Address(r8))
+	a.Movq(asm.X0, asm.Address(si))
+	a.Movq(asm.X1, asm.Address(asm.R9, asm.AX, asm.SX8))
+	a.Pshufb(asm.X0, asm.X1)
+	a.Movq(asm.Address(di), asm.X0)
+	a.Movbqzx(asm.AX, asm.Address(asm.R10, asm.AX, asm.SX1))
+
+	if expand {
+		a.Addq(si, asm.AX)
+		a.Addq(di, asm.Constant(8))
+	} else {
+		a.Addq(di, asm.AX)
+		a.Addq(si, asm.Constant(8))
+	}
+
+	a.Subq(dx, asm.AX)
+	a.Addq(r8, asm.Constant(1))
+	a.Subq(bx, asm.Constant(1))
+	a.Jnz(loop)
+
+	a.Label(done)
+
+	if expand {
+		a.Subq(si, asm.R11)
+		a.Movq(retN, si)
+	} else {
+		a.Subq(di, asm.R11)
+		a.Movq(retN, di)
+	}
+
+	a.Subq(asm.R12, bx)
+	a.Movq(retDone, asm.R12)
+
+	a.Ret()
+}
+
+// compressAVX2ASM generates the equivalent of compressASM on blocks of
+// 16 bytes. The shuffles for both halves of a block are combined into a
+// single VPSHUFB, the high half of dst (or src if expand is set) is
+// stored (or loaded) at the offset given by the count of the low half.
+// The kernel stops once fewer than 16 bytes remain in limit.
+func compressAVX2ASM(a *asm.Asm, name string, expand bool, shuffle, count, half asm.Data) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	mask := a.Argument("mask", 8)
+	limit := a.Argument("limit", 8)
+	blocks := a.Argument("blocks", 8)
+	retN := a.Argument("n", 8)
+	retDone := a.Argument("processed", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+	done := a.NewLabel("done")
+
+	di, si, r8, dx, bx := asm.DI, asm.SI, asm.R8, asm.DX, asm.BX
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(r8, mask)
+	a.Movq(dx, limit)
+	a.Movq(bx, blocks)
+
+	a.Leaq(asm.R9, shuffle)
+	a.Leaq(asm.R10, count)
+
+	if expand {
+		a.Movq(asm.R11, si)
+	} else {
+		a.Movq(asm.R11, di)
+	}
+
+	a.Movq(asm.R12, bx)
+
+	a.Label(loop)
+
+	a.Cmpq(asm.Constant(16), dx)
+	a.Jb(done)
+
+	a.Movbqzx(asm.AX, asm.Address(r8))
+	a.Movbqzx(asm.CX, asm.Address(r8, 1))
+
+	// The shuffle of the high half indexes its own eight bytes.
+	a.Vmovq(asm.X1, asm.Address(asm.R9, asm.AX, asm.SX8))
+	a.Vpinsrq(asm.X1, asm.X1, asm.Address(asm.R9, asm.CX, asm.SX8), asm.Constant(1))
+	a.Vpaddb(asm.X1, asm.X1, half)
+
+	a.Movbqzx(asm.AX, asm.Address(asm.R10, asm.AX, asm.SX1))
+	a.Movbqzx(asm.CX, asm.Address(asm.R10, asm.CX, asm.SX1))
+
+	if expand {
+		a.Vmovq(asm.X0, asm.Address(si))
+		a.Vpinsrq(asm.X0, asm.X0, asm.Address(si, asm.AX, asm.SX1), asm.Constant(1))
+		a.Vpshufb(asm.X0, asm.X0, asm.X1)
+		a.Vmovdqu(asm.Address(di), asm.X0)
+	} else {
+		a.Vmovdqu(asm.X0, asm.Address(si))
+		a.Vpshufb(asm.X0, asm.X0, asm.X1)
+		a.Vmovq(asm.Address(di), asm.X0)
+		a.Vpextrq(asm.Address(di, asm.AX, asm.SX1), asm.X0, asm.Constant(1))
+	}
+
+	a.Addq(asm.AX, asm.CX)
+
+	if expand {
+		a.Addq(si, asm.AX)
+		a.Addq(di, asm.Constant(16))
+	} else {
+		a.Addq(di, asm.AX)
+		a.Addq(si, asm.Constant(16))
+	}
+
+	a.Subq(dx, asm.AX)
+	a.Addq(r8, asm.Constant(2))
+	a.Subq(bx, asm.Constant(1))
+	a.Jnz(loop)
+
+	a.Label(done)
+
+	if expand {
+		a.Subq(si, asm.R11)
+		a.Movq(retN, si)
+	} else {
+		a.Subq(di, asm.R11)
+		a.Movq(retN, di)
+	}
+
+	a.Subq(asm.R12, bx)
+	a.Movq(retDone, asm.R12)
+
+	a.Ret()
+}
+
+// compressAVX512ASM generates the equivalent of compressASM using
+// VPCOMPRESSB or VPEXPANDB on blocks of 64 bytes. The memory forms of
+// the instructions only access the selected bytes of the dense buffer,
+// so the kernel runs until fewer bytes remain in limit than are
+// selected by the next block.
+func compressAVX512ASM(a *asm.Asm, name string, expand bool) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	mask := a.Argument("mask", 8)
+	limit := a.Argument("limit", 8)
+	blocks := a.Argument("blocks", 8)
+	retN := a.Argument("n", 8)
+	retDone := a.Argument("processed", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+	done := a.NewLabel("done")
+
+	di, si, r8, dx, bx := asm.DI, asm.SI, asm.R8, asm.DX, asm.BX
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(r8, mask)
+	a.Movq(dx, limit)
+	a.Movq(bx, blocks)
+
+	if expand {
+		a.Movq(asm.R11, si)
+	} else {
+		a.Movq(asm.R11, di)
+	}
+
+	a.Movq(asm.R12, bx)
+
+	a.Label(loop)
+
+	a.Movq(asm.AX, asm.Address(r8))
+	a.Popcntq(asm.CX, asm.AX)
+	a.Cmpq(asm.CX, dx)
+	a.Jb(done)
+
+	a.Kmovq(asm.K1, asm.AX)
+
+	if expand {
+		a.Vpxorq(asm.Z0, asm.Z0, asm.Z0)
+		a.Vpexpandb(asm.Z0, asm.K1, asm.Address(si))
+		a.Vmovdqu64(asm.Address(di), asm.Z0)
+
+		a.Addq(si, asm.CX)
+		a.Addq(di, asm.Constant(64))
+	} else {
+		a.Vmovdqu64(asm.Z0, asm.Address(si))
+		a.Vpcompressb(asm.Address(di), asm.K1, asm.Z0)
+
+		a.Addq(di, asm.CX)
+		a.Addq(si, asm.Constant(64))
+	}
+
+	a.Subq(dx, asm.CX)
+	a.Addq(r8, asm.Constant(8))
+	a.Subq(bx, asm.Constant(1))
+	a.Jnz(loop)
+
+	a.Label(done)
+
+	if expand {
+		a.Subq(si, asm.R11)
+		a.Movq(retN, si)
+	} else {
+		a.Subq(di, asm.R11)
+		a.Movq(retN, di)
+	}
+
+	a.Subq(asm.R12, bx)
+	a.Movq(retDone, asm.R12)
+
+	a.Vzeroupper()
+	a.Ret()
+}
+
+func compressBytesASM(a *asm.Asm) {
+	var compress, expand, count []byte
+	for m := 0; m < 256; m++ {
+		var c [8]byte
+		var e [8]byte
+
+		k := 0
+		for j := 0; j < 8; j++ {
+			c[j], e[j] = 0x80, 0x80
+
+			if m&(1<<uint(j)) != 0 {
+				c[k], e[j] = byte(j), byte(k)
+				k++
+			}
+		}
 
-func threeArgumentASM(a *asm.Asm, name string, pop, opb func(ops ...asm.Operand)) {
+		compress = append(compress, c[:]...)
+		expand = append(expand, e[:]...)
+	}
+
+	for m := 0; m < 256; m++ {
+		count = append(count, byte(bits.OnesCount8(uint8(m))))
+	}
+
+	countData := a.Data("compressCount", count)
+	compressData := a.Data("compressShuffle", compress)
+	expandData := a.Data("expandShuffle", expand)
+
+	compressASM(a, "compressASM", false, compressData, countData)
+	compressASM(a, "expandASM", true, expandData, countData)
+
+	half := a.Data("compressHalf", append(make([]byte, 8), bytes.Repeat([]byte{8}, 8)...))
+
+	compressAVX2ASM(a, "compressAVX2", false, compressData, countData, half)
+	compressAVX2ASM(a, "expandAVX2", true, expandData, countData, half)
+
+	compressAVX512ASM(a, "compressAVX512", false)
+	compressAVX512ASM(a, "expandAVX512", true)
+}
+
+// maskedASM generates a kernel that sets each byte of dst for which the
+// corresponding bit of mask is set, in LSBFirst order, to the byte of
+// src or, if fill is set, to v. len must be a non-zero multiple of 16.
+// Partially selected blocks are stored with MASKMOVDQU, which leaves the
+// other bytes of dst untouched like the AVX-512 masked store.
+func maskedASM(a *asm.Asm, name string, fill bool, spread, bits asm.Data) {
 	a.NewFunction(name)
 	a.NoSplit()
 
 	dst := a.Argument("dst", 8)
-	srcA := a.Argument("a", 8)
-	srcB := a.Argument("b", 8)
+
+	var src, v asm.Operand
+	if !fill {
+		src = a.Argument("src", 8)
+	}
+
+	mask := a.Argument("mask", 8)
 	length := a.Argument("len", 8)
 
+	if fill {
+		v = a.Argument("v", 8)
+	}
+
 	a.Start()
 
-	hugeloop := a.NewLabel("hugeloop")
-	bigloop := a.NewLabel("bigloop")
 	loop := a.NewLabel("loop")
-	ret := a.NewLabel("ret")
+	full := a.NewLabel("full")
+	next := a.NewLabel("next")
 
-	di, sA, sB, cx := asm.DI, asm.SI, asm.DX, asm.BX
+	di, si, r8, bx := asm.DI, asm.SI, asm.R8, asm.BX
 
 	a.Movq(di, dst)
-	a.Movq(sA, srcA)
-	a.Movq(sB, srcB)
-	a.Movq(cx, length)
+	if !fill {
+		a.Movq(si, src)
+	}
+	a.Movq(r8, mask)
+	a.Movq(bx, length)
 
-	a.Cmpq(asm.Constant(16), cx)
-	a.Jb(loop)
+	if fill {
+		a.Movq(asm.AX, v)
+		broadcastByte(a, sse2, asm.X15, asm.X15)
+	}
 
-	if name == "xnorASM" || name == "nandASM" || name == "norASM" {
-		a.Pcmpeql(asm.X15, asm.X15)
+	a.Movou(asm.X14, spread)
+	a.Movou(asm.X13, bits)
+
+	a.Label(loop)
+
+	data := asm.X15
+	if !fill {
+		data = asm.X1
 	}
 
-	a.Cmpq(asm.Constant(64), cx)
-	a.Jb(bigloop)
+	// MASKMOVDQU only writes the selected bytes to (DI), so the other
+	// bytes of dst may be written concurrently. It is slow, so blocks
+	// where every or no byte is selected are handled separately.
+	a.Movwlzx(asm.CX, asm.Address(r8))
+	a.Testl(asm.CX, asm.CX)
+	a.Jz(next)
 
-	a.Label(hugeloop)
+	if !fill {
+		a.Movou(data, asm.Address(si))
+	}
 
-	a.Movou(asm.X0, asm.Address(sA, cx, asm.SX1, -16))
-	a.Movou(asm.X2, asm.Address(sA, cx, asm.SX1, -32))
-	a.Movou(asm.X4, asm.Address(sA, cx, asm.SX1, -48))
-	a.Movou(asm.X6, asm.Address(sA, cx, asm.SX1, -64))
+	a.Cmpl(asm.Constant(0xffff), asm.CX)
+	a.Je(full)
 
-	a.Movou(asm.X1, asm.Address(sB, cx, asm.SX1, -16))
-	a.Movou(asm.X3, asm.Address(sB, cx, asm.SX1, -32))
-	a.Movou(asm.X5, asm.Address(sB, cx, asm.SX1, -48))
-	a.Movou(asm.X7, asm.Address(sB, cx, asm.SX1, -64))
+	bitsToBytes(a, asm.X0, asm.Address(r8))
+	a.Maskmovou(data, asm.X0)
+	a.Jmp(next)
 
-	pop(asm.X1, asm.X0)
-	pop(asm.X3, asm.X2)
-	pop(asm.X5, asm.X4)
-	pop(asm.X7, asm.X6)
+	a.Label(full)
+	a.Movou(asm.Address(di), data)
 
-	a.Movou(asm.Address(di, cx, asm.SX1, -16), asm.X1)
-	a.Movou(asm.Address(di, cx, asm.SX1, -32), asm.X3)
-	a.Movou(asm.Address(di, cx, asm.SX1, -48), asm.X5)
-	a.Movou(asm.Address(di, cx, asm.SX1, -64), asm.X7)
+	a.Label(next)
 
-	a.Subq(cx, asm.Constant(64))
-	a.Jz(ret)
+	if !fill {
+		a.Addq(si, asm.Constant(16))
+	}
 
-	a.Cmpq(asm.Constant(64), cx)
-	a.Jae(hugeloop)
+	a.Addq(di, asm.Constant(16))
+	a.Addq(r8, asm.Constant(2))
+	a.Subq(bx, asm.Constant(16))
+	a.Jnz(loop)
 
-	a.Cmpq(asm.Constant(16), cx)
-	a.Jb(loop)
+	// MASKMOVDQU stores are weakly ordered.
+	a.Sfence()
+	a.Ret()
+}
 
-	a.Label(bigloop)
+// maskedAVX512ASM generates the equivalent of maskedASM using masked
+// stores of 64 bytes. len must be a non-zero multiple of 64.
+func maskedAVX512ASM(a *asm.Asm, name string, fill bool) {
+	a.NewFunction(name)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
 
-	a.Movou(asm.X0, asm.Address(sA, cx, asm.SX1, -16))
-	a.Movou(asm.X1, asm.Address(sB, cx, asm.SX1, -16))
+	var src, v asm.Operand
+	if !fill {
+		src = a.Argument("src", 8)
+	}
 
-	pop(asm.X1, asm.X0)
+	mask := a.Argument("mask", 8)
+	length := a.Argument("len", 8)
 
-	a.Movou(asm.Address(di, cx, asm.SX1, -16), asm.X1)
+	if fill {
+		v = a.Argument("v", 8)
+	}
 
-	a.Subq(cx, asm.Constant(16))
-	a.Jz(ret)
+	a.Start()
 
-	a.Cmpq(asm.Constant(16), cx)
-	a.Jae(bigloop)
+	loop := a.NewLabel("loop")
+
+	di, si, r8, bx := asm.DI, asm.SI, asm.R8, asm.BX
+
+	a.Movq(di, dst)
+	if !fill {
+		a.Movq(si, src)
+	}
+	a.Movq(r8, mask)
+	a.Movq(bx, length)
+
+	if fill {
+		a.Movq(asm.AX, v)
+		a.Movq(asm.X15, asm.AX)
+		a.Vpbroadcastb(asm.Z15, asm.X15)
+	}
 
 	a.Label(loop)
 
-	a.Movb(asm.AX, asm.Address(sA, cx, asm.SX1, -1))
-	opb(asm.AX, asm.Address(sB, cx, asm.SX1, -1))
-	a.Movb(asm.Address(di, cx, asm.SX1, -1), asm.AX)
+	a.Kmovq(asm.K1, asm.Address(r8))
 
-	a.Subq(cx, asm.Constant(1))
-	a.Jnz(loop)
+	if fill {
+		a.Vmovdqu8(asm.Address(di), asm.K1, asm.Z15)
+	} else {
+		a.Vmovdqu8(asm.Z0, asm.Address(si))
+		a.Vmovdqu8(asm.Address(di), asm.K1, asm.Z0)
 
-	a.Label(ret)
+		a.Addq(si, asm.Constant(64))
+	}
 
+	a.Addq(di, asm.Constant(64))
+	a.Addq(r8, asm.Constant(8))
+	a.Subq(bx, asm.Constant(64))
+	a.Jnz(loop)
+
+	a.Vzeroupper()
 	a.Ret()
 }
 
-func xorASM(a *asm.Asm) {
-	threeArgumentASM(a, "xorASM", a.Pxor, a.Xorb)
-}
+func maskedBytesASM(a *asm.Asm) {
+	spread, lsb, _ := bitTables()
 
-func xnorASM(a *asm.Asm) {
-	threeArgumentASM(a, "xnorASM", func(ops ...asm.Operand) {
-		if len(ops) != 2 {
-			panic("wrong number of operands")
-		}
+	spreadData := a.Data("maskedSpread", spread)
+	bits := a.Data("maskedBits", lsb)
 
-		a.Pxor(ops[0], ops[1])
-		a.Pxor(ops[0], asm.X15)
-	}, func(ops ...asm.Operand) {
-		if len(ops) != 2 {
-			panic("wrong number of operands")
-		}
+	maskedASM(a, "copyMaskedASM", false, spreadData, bits)
+	maskedASM(a, "fillMaskedASM", true, spreadData, bits)
 
-		a.Xorb(ops[0], ops[1])
-		a.Notb(ops[0])
-	})
+	maskedAVX512ASM(a, "copyMaskedAVX512", false)
+	maskedAVX512ASM(a, "fillMaskedAVX512", true)
 }
 
-func andASM(a *asm.Asm) {
-	threeArgumentASM(a, "andASM", a.Pand, a.Andb)
-}
+// translateASM generates a kernel that sets each byte of dst to the
+// entry of the 256 byte table indexed by the byte of src, using the
+// instructions of v. len must be a non-zero multiple of v.width.
+//
+// The table is processed as sixteen rows of sixteen bytes. For row k,
+// 16*k is subtracted from each byte of src and 0x70 is added with
+// unsigned saturation, so bytes in the row become valid PSHUFB indices
+// and every other byte has the most significant bit set and is looked
+// up as zero.
+func translateASM(a *asm.Asm, v vector, bias, step asm.Data) {
+	a.NewFunction("translate" + v.suffix)
+	a.NoSplit()
 
-func andNotASM(a *asm.Asm) {
-	threeArgumentASM(a, "andNotASM", a.Pandn, func(ops ...asm.Operand) {
-		if len(ops) != 2 {
-			panic("wrong number of operands")
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	length := a.Argument("len", 8)
+	table := a.Argument("table", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	di, si, bx, dx := asm.DI, asm.SI, asm.BX, asm.DX
+	x, acc, idx, row := v.regs[0], v.regs[1], v.regs[2], v.regs[3]
+	biasR, stepR := v.regs[15], v.regs[14]
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(bx, length)
+	a.Movq(dx, table)
+
+	v.movu(a, biasR, bias)
+	v.movu(a, stepR, step)
+
+	a.Label(loop)
+
+	v.movu(a, x, asm.Address(si))
+
+	for k := 0; k < 16; k++ {
+		if v.width == 16 {
+			a.Movou(idx, x)
+			a.Paddusb(idx, biasR)
+			a.Movou(row, asm.Address(dx, 16*k))
+			a.Pshufb(row, idx)
+		} else {
+			a.Vpaddusb(idx, x, biasR)
+			a.Vbroadcasti128(row, asm.Address(dx, 16*k))
+			a.Vpshufb(row, row, idx)
 		}
 
-		a.Movb(asm.R15, ops[1])
-		a.Notb(asm.R15)
-		a.Andb(ops[0], asm.R15)
-	})
-}
+		switch {
+		case k == 0 && v.width == 16:
+			a.Movou(acc, row)
+		case k == 0:
+			a.Vmovdqa(acc, row)
+		case v.width == 16:
+			a.Por(acc, row)
+		default:
+			a.Vpor(acc, acc, row)
+		}
 
-func nandASM(a *asm.Asm) {
-	threeArgumentASM(a, "nandASM", func(ops ...asm.Operand) {
-		if len(ops) != 2 {
-			panic("wrong number of operands")
+		if k == 15 {
+			break
 		}
 
-		a.Pand(ops[0], ops[1])
-		a.Pxor(ops[0], asm.X15)
-	}, func(ops ...asm.Operand) {
-		if len(ops) != 2 {
-			panic("wrong number of operands")
+		if v.width == 16 {
+			a.Psubb(x, stepR)
+		} else {
+			a.Vpsubb(x, x, stepR)
 		}
+	}
 
-		a.Andb(ops[0], ops[1])
-		a.Notb(ops[0])
-	})
+	v.movu(a, asm.Address(di), acc)
+
+	a.Addq(si, asm.Constant(v.width))
+	a.Addq(di, asm.Constant(v.width))
+	a.Subq(bx, asm.Constant(v.width))
+	a.Jnz(loop)
+
+	if v.width != 16 {
+		a.Vzeroupper()
+	}
+
+	a.Ret()
 }
 
-func orASM(a *asm.Asm) {
-	threeArgumentASM(a, "orASM", a.Por, a.Orb)
+// lookup16ASM generates a kernel that sets each byte of dst to the XOR
+// of the entries of the lo and hi tables indexed by the low and high
+// nibbles of the byte of src, using the instructions of v. len must be
+// a non-zero multiple of v.width.
+func lookup16ASM(a *asm.Asm, v vector, mask asm.Data) {
+	a.NewFunction("lookup16" + v.suffix)
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	length := a.Argument("len", 8)
+	lo := a.Argument("lo", 8)
+	hi := a.Argument("hi", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	di, si, bx := asm.DI, asm.SI, asm.BX
+	x, y, rlo, rhi := v.regs[0], v.regs[1], v.regs[2], v.regs[3]
+	maskR, loR, hiR := v.regs[13], v.regs[12], v.regs[11]
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(bx, length)
+
+	a.Movq(asm.AX, lo)
+	a.Movq(asm.DX, hi)
+
+	v.movu(a, maskR, mask)
+
+	if v.width == 16 {
+		a.Movou(loR, asm.Address(asm.AX))
+		a.Movou(hiR, asm.Address(asm.DX))
+	} else {
+		a.Vbroadcasti128(loR, asm.Address(asm.AX))
+		a.Vbroadcasti128(hiR, asm.Address(asm.DX))
+	}
+
+	a.Label(loop)
+
+	v.movu(a, x, asm.Address(si))
+
+	if v.width == 16 {
+		a.Movou(y, x)
+		a.Psrlw(y, asm.Constant(4))
+		a.Pand(x, maskR)
+		a.Pand(y, maskR)
+
+		a.Movou(rlo, loR)
+		a.Movou(rhi, hiR)
+		a.Pshufb(rlo, x)
+		a.Pshufb(rhi, y)
+		a.Pxor(rlo, rhi)
+	} else {
+		a.Vpsrlw(y, x, asm.Constant(4))
+		a.Vpand(x, x, maskR)
+		a.Vpand(y, y, maskR)
+
+		a.Vpshufb(rlo, loR, x)
+		a.Vpshufb(rhi, hiR, y)
+		a.Vpxor(rlo, rlo, rhi)
+	}
+
+	v.movu(a, asm.Address(di), rlo)
+
+	a.Addq(si, asm.Constant(v.width))
+	a.Addq(di, asm.Constant(v.width))
+	a.Subq(bx, asm.Constant(v.width))
+	a.Jnz(loop)
+
+	if v.width != 16 {
+		a.Vzeroupper()
+	}
+
+	a.Ret()
 }
 
-func norASM(a *asm.Asm) {
-	threeArgumentASM(a, "norASM", func(ops ...asm.Operand) {
-		if len(ops) != 2 {
-			panic("wrong number of operands")
-		}
+func translateBytesASM(a *asm.Asm) {
+	bias := a.Data("translateBias", bytes.Repeat([]byte{0x70}, 32))
+	step := a.Data("translateStep", bytes.Repeat([]byte{0x10}, 32))
+	mask := a.Data("lookupMask", bytes.Repeat([]byte{0x0f}, 32))
 
-		a.Por(ops[0], ops[1])
-		a.Pxor(ops[0], asm.X15)
-	}, func(ops ...asm.Operand) {
-		if len(ops) != 2 {
-			panic("wrong number of operands")
-		}
+	translateASM(a, sse2, bias, step)
+	translateASM(a, avx2, bias, step)
 
-		a.Orb(ops[0], ops[1])
-		a.Notb(ops[0])
-	})
+	lookup16ASM(a, sse2, mask)
+	lookup16ASM(a, avx2, mask)
 }
 
-func notASM(a *asm.Asm) {
-	a.NewFunction("notASM")
+// prefixXORASM generates a kernel that sets each bit of dst to the XOR
+// of all the bits of src up to and including it, treating the buffers
+// as a single little-endian bit vector, and XORs in carry, which is
+// zero or all ones. len must be a non-zero multiple of 8. The carry for
+// the next call is returned.
+//
+// The prefix XOR of a quadword is its carry-less product with all ones.
+func prefixXORASM(a *asm.Asm) {
+	a.NewFunction("prefixXORASM")
 	a.NoSplit()
 
 	dst := a.Argument("dst", 8)
 	src := a.Argument("src", 8)
 	length := a.Argument("len", 8)
+	carry := a.Argument("carry", 8)
+	ret := a.Argument("ret", 8)
 
 	a.Start()
 
-	hugeloop := a.NewLabel("hugeloop")
-	bigloop := a.NewLabel("bigloop")
 	loop := a.NewLabel("loop")
-	ret := a.NewLabel("ret")
 
-	di, si, cx := asm.DI, asm.SI, asm.BX
+	di, si, bx, dx := asm.DI, asm.SI, asm.BX, asm.DX
 
 	a.Movq(di, dst)
 	a.Movq(si, src)
-	a.Movq(cx, length)
+	a.Movq(bx, length)
+	a.Movq(dx, carry)
 
-	a.Cmpq(asm.Constant(16), cx)
-	a.Jb(loop)
+	a.Pcmpeqb(asm.X1, asm.X1)
 
-	a.Pcmpeql(asm.X0, asm.X0)
+	a.Label(loop)
 
-	a.Cmpq(asm.Constant(64), cx)
-	a.Jb(bigloop)
+	a.Movq(asm.X0, asm.Address(si))
+	a.Pclmulqdq(asm.X0, asm.X1, asm.Constant(0))
+	a.Movq(asm.AX, asm.X0)
+	a.Xorq(asm.AX, dx)
+	a.Movq(asm.Address(di), asm.AX)
 
-	a.Label(hugeloop)
+	// The carry is all ones if the last bit is set.
+	a.Sarq(asm.AX, asm.Constant(63))
+	a.Movq(dx, asm.AX)
 
-	a.Movou(asm.X1, asm.Address(si, cx, asm.SX1, -16))
-	a.Movou(asm.X2, asm.Address(si, cx, asm.SX1, -32))
-	a.Movou(asm.X3, asm.Address(si, cx, asm.SX1, -48))
-	a.Movou(asm.X4, asm.Address(si, cx, asm.SX1, -64))
+	a.Addq(si, asm.Constant(8))
+	a.Addq(di, asm.Constant(8))
+	a.Subq(bx, asm.Constant(8))
+	a.Jnz(loop)
 
-	a.Pxor(asm.X1, asm.X0)
-	a.Pxor(asm.X2, asm.X0)
-	a.Pxor(asm.X3, asm.X0)
-	a.Pxor(asm.X4, asm.X0)
+	a.Movq(ret, dx)
+	a.Ret()
+}
 
-	a.Movou(asm.Address(di, cx, asm.SX1, -16), asm.X1)
-	a.Movou(asm.Address(di, cx, asm.SX1, -32), asm.X2)
-	a.Movou(asm.Address(di, cx, asm.SX1, -48), asm.X3)
-	a.Movou(asm.Address(di, cx, asm.SX1, -64), asm.X4)
+// clmulLanesASM generates a kernel that sets each 16 bytes of dst to
+// the carry-less product of the corresponding quadwords of a and b. n
+// is the number of quadwords and must be non-zero.
+func clmulLanesASM(a *asm.Asm) {
+	a.NewFunction("clmulLanesASM")
+	a.NoSplit()
 
-	a.Subq(cx, asm.Constant(64))
-	a.Jz(ret)
+	dst := a.Argument("dst", 8)
+	srcA := a.Argument("a", 8)
+	srcB := a.Argument("b", 8)
+	n := a.Argument("n", 8)
 
-	a.Cmpq(asm.Constant(64), cx)
-	a.Jae(hugeloop)
+	a.Start()
 
-	a.Cmpq(asm.Constant(16), cx)
-	a.Jb(loop)
+	loop := a.NewLabel("loop")
 
-	a.Label(bigloop)
+	di, si, dx, bx := asm.DI, asm.SI, asm.DX, asm.BX
 
-	a.Movou(asm.X1, asm.Address(si, cx, asm.SX1, -16))
-	a.Pxor(asm.X1, asm.X0)
-	a.Movou(asm.Address(di, cx, asm.SX1, -16), asm.X1)
+	a.Movq(di, dst)
+	a.Movq(si, srcA)
+	a.Movq(dx, srcB)
+	a.Movq(bx, n)
 
-	a.Subq(cx, asm.Constant(16))
-	a.Jz(ret)
+	a.Label(loop)
 
-	a.Cmpq(asm.Constant(16), cx)
-	a.Jae(bigloop)
+	a.Movq(asm.X0, asm.Address(si))
+	a.Movq(asm.X1, asm.Address(dx))
+	a.Pclmulqdq(asm.X0, asm.X1, asm.Constant(0))
+	a.Movou(asm.Address(di), asm.X0)
+
+	a.Addq(si, asm.Constant(8))
+	a.Addq(dx, asm.Constant(8))
+	a.Addq(di, asm.Constant(16))
+	a.Subq(bx, asm.Constant(1))
+	a.Jnz(loop)
+
+	a.Ret()
+}
+
+// clmulAddASM generates a kernel that XORs the carry-less product of
+// the quadword x and the n quadwords at src into the n+1 quadwords at
+// dst. n must be non-zero.
+func clmulAddASM(a *asm.Asm) {
+	a.NewFunction("clmulAddASM")
+	a.NoSplit()
+
+	dst := a.Argument("dst", 8)
+	src := a.Argument("src", 8)
+	n := a.Argument("n", 8)
+	x := a.Argument("x", 8)
+
+	a.Start()
+
+	loop := a.NewLabel("loop")
+
+	di, si, bx := asm.DI, asm.SI, asm.BX
+
+	a.Movq(di, dst)
+	a.Movq(si, src)
+	a.Movq(bx, n)
+	a.Movq(asm.X1, x)
+
+	// X3 holds the high quadword of the previous product.
+	a.Pxor(asm.X3, asm.X3)
 
 	a.Label(loop)
 
-	a.Movb(asm.AX, asm.Address(si, cx, asm.SX1, -1))
-	a.Notb(asm.AX)
-	a.Movb(asm.Address(di, cx, asm.SX1, -1), asm.AX)
+	a.Movq(asm.X0, asm.Address(si))
+	a.Pclmulqdq(asm.X0, asm.X1, asm.Constant(0))
+	a.Pxor(asm.X0, asm.X3)
 
-	a.Subq(cx, asm.Constant(1))
+	a.Movq(asm.X2, asm.Address(di))
+	a.Pxor(asm.X2, asm.X0)
+	a.Movq(asm.Address(di), asm.X2)
+
+	a.Psrldq(asm.X0, asm.Constant(8))
+	a.Movou(asm.X3, asm.X0)
+
+	a.Addq(si, asm.Constant(8))
+	a.Addq(di, asm.Constant(8))
+	a.Subq(bx, asm.Constant(1))
 	a.Jnz(loop)
 
-	a.Label(ret)
+	a.Movq(asm.X2, asm.Address(di))
+	a.Pxor(asm.X2, asm.X3)
+	a.Movq(asm.Address(di), asm.X2)
 
 	a.Ret()
 }
 
+func clmulASM(a *asm.Asm) {
+	clmulLanesASM(a)
+	clmulAddASM(a)
+}
+
 func main() {
 	if err := asm.Do("bitwise_xor_amd64.s", header, xorASM); err != nil {
 		panic(err)
@@ -303,4 +1843,40 @@ func main() {
 	if err := asm.Do("bitwise_not_amd64.s", header, notASM); err != nil {
 		panic(err)
 	}
+
+	if err := asm.Do("bitwise_galois_amd64.s", header, galoisMulXORASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_popcnt_amd64.s", header, popcntASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_mask_amd64.s", header, compareMaskASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_pack_amd64.s", header, packBitsASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_compress_amd64.s", header, compressBytesASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_masked_amd64.s", header, maskedBytesASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_translate_amd64.s", header, translateBytesASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_prefix_amd64.s", header, prefixXORASM); err != nil {
+		panic(err)
+	}
+
+	if err := asm.Do("bitwise_clmul_amd64.s", header, clmulASM); err != nil {
+		panic(err)
+	}
 }
@@ -0,0 +1,191 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build ignore
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+)
+
+const headerARM64 = `// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+//
+// This file is auto-generated - do not modify
+
+// +build arm64,!gccgo,!appengine,!purego
+
+#include "textflag.h"
+`
+
+// neonOp describes a bitwise operation for neonASM.
+type neonOp struct {
+	name string
+	file string
+
+	// vop and op are the vector and scalar instructions that set
+	// their last operand to the first source combined with the
+	// second. vop is empty for Not.
+	vop, op string
+
+	// negate is set if the result of vop or op must be inverted.
+	negate bool
+}
+
+var neonOps = []neonOp{
+	{"xor", "xor", "VEOR", "EOR", false},
+	{"xnor", "xnor", "VEOR", "EOR", true},
+	{"and", "and", "VAND", "AND", false},
+	{"andNot", "andnot", "VBIC", "BIC", false},
+	{"nand", "nand", "VAND", "AND", true},
+	{"or", "or", "VORR", "ORR", false},
+	{"nor", "nor", "VORR", "ORR", true},
+	{"not", "not", "", "", true},
+}
+
+type asmWriter struct {
+	bytes.Buffer
+}
+
+func (w *asmWriter) label(name string) {
+	fmt.Fprintf(w, "%s:\n", name)
+}
+
+func (w *asmWriter) inst(op string, args ...string) {
+	if len(args) == 0 {
+		fmt.Fprintf(w, "\t%s\n", op)
+		return
+	}
+
+	fmt.Fprintf(w, "\t%s %s\n", op, strings.Join(args, ", "))
+}
+
+// vregs returns the register list for n vector registers starting at
+// first.
+func vregs(first, n int) string {
+	regs := make([]string, n)
+	for i := range regs {
+		regs[i] = fmt.Sprintf("V%d.B16", first+i)
+	}
+
+	return "[" + strings.Join(regs, ", ") + "]"
+}
+
+// neonASM generates the kernel for op. The buffers are processed from
+// the start in blocks of 64, 16, 8 and then 1 byte.
+//
+// R0, R1 and R2 hold dst, a and b (or src) and are advanced by the post
+// indexed loads and stores; R3 holds the remaining length.
+func neonASM(w *asmWriter, op neonOp) {
+	twoSrcs := op.vop != ""
+
+	size := 32
+	if !twoSrcs {
+		size = 24
+	}
+
+	fmt.Fprintf(w, "\nTEXT ·%sNEON(SB),NOSPLIT,$0-%d\n", op.name, size)
+	w.inst("MOVD", "dst+0(FP)", "R0")
+	if twoSrcs {
+		w.inst("MOVD", "a+8(FP)", "R1")
+		w.inst("MOVD", "b+16(FP)", "R2")
+		w.inst("MOVD", "len+24(FP)", "R3")
+	} else {
+		w.inst("MOVD", "src+8(FP)", "R1")
+		w.inst("MOVD", "len+16(FP)", "R3")
+	}
+
+	// vector applies op to n registers, with a in V0 to Vn-1 and b in
+	// V4 to V4+n-1.
+	vector := func(n int) {
+		for i := 0; i < n; i++ {
+			a := fmt.Sprintf("V%d.B16", i)
+			if twoSrcs {
+				w.inst(op.vop, fmt.Sprintf("V%d.B16", 4+i), a, a)
+			}
+
+			if op.negate {
+				w.inst("VNOT", a, a)
+			}
+		}
+	}
+
+	block := func(n int) {
+		w.inst("VLD1.P", fmt.Sprintf("%d(R1)", 16*n), vregs(0, n))
+		if twoSrcs {
+			w.inst("VLD1.P", fmt.Sprintf("%d(R2)", 16*n), vregs(4, n))
+		}
+
+		vector(n)
+
+		w.inst("VST1.P", vregs(0, n), fmt.Sprintf("%d(R0)", 16*n))
+	}
+
+	// scalar applies op to R4, the first source, and R5.
+	scalar := func(load, store string, n int) {
+		w.inst(load, fmt.Sprintf("%d(R1)", n), "R4")
+		if twoSrcs {
+			w.inst(load, fmt.Sprintf("%d(R2)", n), "R5")
+			w.inst(op.op, "R5", "R4", "R4")
+		}
+
+		if op.negate {
+			w.inst("MVN", "R4", "R4")
+		}
+
+		w.inst(store, "R4", fmt.Sprintf("%d(R0)", n))
+	}
+
+	w.inst("CMP", "$64", "R3")
+	w.inst("BLT", "loop16")
+
+	w.label("loop64")
+	block(4)
+	w.inst("SUB", "$64", "R3")
+	w.inst("CMP", "$64", "R3")
+	w.inst("BGE", "loop64")
+
+	w.label("loop16")
+	w.inst("CMP", "$16", "R3")
+	w.inst("BLT", "loop8")
+	block(1)
+	w.inst("SUB", "$16", "R3")
+	w.inst("B", "loop16")
+
+	w.label("loop8")
+	w.inst("CMP", "$8", "R3")
+	w.inst("BLT", "loop1")
+	scalar("MOVD.P", "MOVD.P", 8)
+	w.inst("SUB", "$8", "R3")
+	w.inst("B", "loop8")
+
+	w.label("loop1")
+	w.inst("CBZ", "R3", "ret")
+	scalar("MOVBU.P", "MOVB.P", 1)
+	w.inst("SUB", "$1", "R3")
+	w.inst("B", "loop1")
+
+	w.label("ret")
+	w.inst("RET")
+}
+
+func main() {
+	for _, op := range neonOps {
+		var w asmWriter
+		w.WriteString(headerARM64)
+		neonASM(&w, op)
+
+		file := fmt.Sprintf("bitwise_%s_arm64.s", op.file)
+		if err := ioutil.WriteFile(file, w.Bytes(), 0644); err != nil {
+			panic(err)
+		}
+	}
+}
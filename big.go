@@ -0,0 +1,143 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build go1.18
+
+package bitwise
+
+import "math/big"
+
+// The Big functions operate on the two's complement representation of
+// their arguments, as the equivalent math/big methods do. Negative
+// values are handled by way of their complement, -x-1, which is
+// non-negative. Slices of big.Word may be used directly with the Words
+// functions.
+
+// BigXOR sets z = x XOR y and returns z.
+func BigXOR(z, x, y *big.Int) *big.Int {
+	switch xn, yn := x.Sign() < 0, y.Sign() < 0; {
+	case !xn && !yn:
+		return bigXOR(z, x, y)
+	case xn && yn:
+		return bigXOR(z, bigNot(x), bigNot(y))
+	case xn:
+		x, y = y, x
+		fallthrough
+	default:
+		// x >= 0, y < 0
+		return z.Not(bigXOR(z, x, bigNot(y)))
+	}
+}
+
+// BigAnd sets z = x AND y and returns z.
+func BigAnd(z, x, y *big.Int) *big.Int {
+	switch xn, yn := x.Sign() < 0, y.Sign() < 0; {
+	case !xn && !yn:
+		return bigAnd(z, x, y)
+	case xn && yn:
+		return z.Not(bigOr(z, bigNot(x), bigNot(y)))
+	case xn:
+		x, y = y, x
+		fallthrough
+	default:
+		// x >= 0, y < 0
+		return bigAndNot(z, x, bigNot(y))
+	}
+}
+
+// BigAndNot sets z = x AND (NOT y) and returns z.
+func BigAndNot(z, x, y *big.Int) *big.Int {
+	switch xn, yn := x.Sign() < 0, y.Sign() < 0; {
+	case !xn && !yn:
+		return bigAndNot(z, x, y)
+	case xn && yn:
+		return bigAndNot(z, bigNot(y), bigNot(x))
+	case xn:
+		return z.Not(bigOr(z, bigNot(x), y))
+	default:
+		// x >= 0, y < 0
+		return bigAnd(z, x, bigNot(y))
+	}
+}
+
+// BigOr sets z = x OR y and returns z.
+func BigOr(z, x, y *big.Int) *big.Int {
+	switch xn, yn := x.Sign() < 0, y.Sign() < 0; {
+	case !xn && !yn:
+		return bigOr(z, x, y)
+	case xn && yn:
+		return z.Not(bigAnd(z, bigNot(x), bigNot(y)))
+	case xn:
+		x, y = y, x
+		fallthrough
+	default:
+		// x >= 0, y < 0
+		return z.Not(bigAndNot(z, bigNot(y), x))
+	}
+}
+
+// bigNot returns -x-1 as a new big.Int.
+func bigNot(x *big.Int) *big.Int {
+	return new(big.Int).Not(x)
+}
+
+// bigWords returns a slice of n words, reusing the storage of z if
+// possible. z may be the same as either operand as the kernels allow
+// dst to be the same as a source.
+func bigWords(z *big.Int, n int) []big.Word {
+	if b := z.Bits(); cap(b) >= n {
+		return b[:n]
+	}
+
+	return make([]big.Word, n)
+}
+
+// bigXOR sets z = x XOR y for non-negative x and y.
+func bigXOR(z, x, y *big.Int) *big.Int {
+	xb, yb := x.Bits(), y.Bits()
+	if len(xb) < len(yb) {
+		xb, yb = yb, xb
+	}
+
+	zb := bigWords(z, len(xb))
+	XORWords(zb, xb, yb)
+	copy(zb[len(yb):], xb[len(yb):])
+	return z.SetBits(zb)
+}
+
+// bigAnd sets z = x AND y for non-negative x and y.
+func bigAnd(z, x, y *big.Int) *big.Int {
+	xb, yb := x.Bits(), y.Bits()
+	if len(xb) < len(yb) {
+		xb, yb = yb, xb
+	}
+
+	zb := bigWords(z, len(yb))
+	AndWords(zb, xb, yb)
+	return z.SetBits(zb)
+}
+
+// bigAndNot sets z = x AND (NOT y) for non-negative x and y.
+func bigAndNot(z, x, y *big.Int) *big.Int {
+	xb, yb := x.Bits(), y.Bits()
+
+	zb := bigWords(z, len(xb))
+	n := AndNotWords(zb, xb, yb)
+	copy(zb[n:], xb[n:])
+	return z.SetBits(zb)
+}
+
+// bigOr sets z = x OR y for non-negative x and y.
+func bigOr(z, x, y *big.Int) *big.Int {
+	xb, yb := x.Bits(), y.Bits()
+	if len(xb) < len(yb) {
+		xb, yb = yb, xb
+	}
+
+	zb := bigWords(z, len(xb))
+	OrWords(zb, xb, yb)
+	copy(zb[len(yb):], xb[len(yb):])
+	return z.SetBits(zb)
+}
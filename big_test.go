@@ -0,0 +1,91 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build go1.18
+
+package bitwise
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+func randBig(r *rand.Rand) *big.Int {
+	b := make([]byte, r.Intn(300))
+	r.Read(b)
+
+	x := new(big.Int).SetBytes(b)
+	if r.Intn(2) == 0 {
+		x.Neg(x)
+	}
+
+	return x
+}
+
+func testBig(t *testing.T, name string, fn, std func(z, x, y *big.Int) *big.Int) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 2000; i++ {
+		x, y := randBig(r), randBig(r)
+		want := std(new(big.Int), x, y)
+
+		if got := fn(new(big.Int), x, y); got.Cmp(want) != 0 {
+			t.Fatalf("%s(%d, %d) = %d, expected %d", name, x, y, got, want)
+		}
+
+		// z may be the same as either operand.
+		z := new(big.Int).Set(x)
+		if fn(z, z, y); z.Cmp(want) != 0 {
+			t.Fatalf("%s(x, x, %d) with x = %d gives %d, expected %d", name, y, x, z, want)
+		}
+
+		z = new(big.Int).Set(y)
+		if fn(z, x, z); z.Cmp(want) != 0 {
+			t.Fatalf("%s(y, %d, y) with y = %d gives %d, expected %d", name, x, y, z, want)
+		}
+	}
+}
+
+func TestBigXOR(t *testing.T) {
+	testBig(t, "BigXOR", BigXOR, (*big.Int).Xor)
+}
+
+func TestBigAnd(t *testing.T) {
+	testBig(t, "BigAnd", BigAnd, (*big.Int).And)
+}
+
+func TestBigAndNot(t *testing.T) {
+	testBig(t, "BigAndNot", BigAndNot, (*big.Int).AndNot)
+}
+
+func TestBigOr(t *testing.T) {
+	testBig(t, "BigOr", BigOr, (*big.Int).Or)
+}
+
+func benchmarkBig(b *testing.B, fn func(z, x, y *big.Int) *big.Int) {
+	r := rand.New(rand.NewSource(1))
+
+	buf := make([]byte, 8*1024)
+	r.Read(buf)
+	x := new(big.Int).SetBytes(buf)
+	r.Read(buf)
+	y := new(big.Int).SetBytes(buf)
+
+	z := new(big.Int)
+	b.SetBytes(int64(len(buf)))
+
+	for i := 0; i < b.N; i++ {
+		fn(z, x, y)
+	}
+}
+
+func BenchmarkBigXOR(b *testing.B) {
+	benchmarkBig(b, BigXOR)
+}
+
+func BenchmarkBigXORStdlib(b *testing.B) {
+	benchmarkBig(b, (*big.Int).Xor)
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import "unsafe"
+
+// realignWords is the number of words of each source that alignedBytes
+// realigns at a time.
+const realignWords = 64
+
+var littleEndian = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// wordOffset returns the offset of b from the previous word boundary.
+func wordOffset(b []byte) int {
+	return int(uintptr(unsafe.Pointer(&b[0])) % uintptr(wordSize))
+}
+
+// wordBytes returns the bytes of w.
+func wordBytes(w *uintptr) []byte {
+	return (*[wordSize]byte)(unsafe.Pointer(w))[:]
+}
+
+// realign sets each word of dst to the next wordSize bytes of src, which
+// must have at least len(dst)*wordSize bytes. Only aligned words are
+// loaded from src, they are combined with shifts.
+func realign(dst []uintptr, src []byte) {
+	if len(dst) == 0 {
+		return
+	}
+
+	off := wordOffset(src)
+	if off == 0 {
+		sw := *(*[]uintptr)(unsafe.Pointer(&src))
+		copy(dst, sw[:len(dst)])
+		return
+	}
+
+	// The first word straddles the word boundary before src, so it
+	// is copied a byte at a time.
+	copy(wordBytes(&dst[0]), src)
+
+	// src[k:] is aligned and contains nw whole words.
+	k := wordSize - off
+	aligned := src[k:]
+	nw := len(aligned) / wordSize
+	sw := *(*[]uintptr)(unsafe.Pointer(&aligned))
+
+	// Each word after the first is made of the last wordSize-off
+	// bytes of one aligned word and the first off bytes of the next.
+	hi, lo := uint(8*off), uint(8*k)
+
+	for j := 1; j < len(dst); j++ {
+		switch {
+		case j >= nw:
+			copy(wordBytes(&dst[j]), src[j*wordSize:])
+		case littleEndian:
+			dst[j] = sw[j-1]>>hi | sw[j]<<lo
+		default:
+			dst[j] = sw[j-1]<<hi | sw[j]>>lo
+		}
+	}
+}
+
+// alignedBytes implements an operation for architectures that do not
+// support unaligned access. fast, which operates on words, is used once
+// dst has been aligned by processing a byte prologue with safe. Sources
+// that do not share the alignment of dst are realigned into a buffer.
+func alignedBytes(dst, a, b []byte, fast, safe func(dst, a, b []byte) int) int {
+	n := minLen(dst, a, b)
+	if n == 0 {
+		return 0
+	}
+
+	p := (wordSize - wordOffset(dst)) % wordSize
+	if p > n {
+		p = n
+	}
+
+	safe(dst[:p], a[:p], b[:p])
+	dst, a, b = dst[p:n], a[p:n], b[p:n]
+
+	if len(dst) == 0 {
+		return n
+	}
+
+	if wordOffset(a) == 0 && wordOffset(b) == 0 {
+		fast(dst, a, b)
+		return n
+	}
+
+	var bufA, bufB [realignWords]uintptr
+
+	for len(dst) > 0 {
+		m := len(dst)
+		if m > realignWords*wordSize {
+			m = realignWords * wordSize
+		}
+
+		// Any trailing partial word is processed by fast a byte
+		// at a time, so only whole words need to be realigned.
+		w, sa, sb := m/wordSize, a[:m], b[:m]
+
+		if wordOffset(a) != 0 && w > 0 {
+			realign(bufA[:w], a)
+			sa = (*[realignWords * wordSize]byte)(unsafe.Pointer(&bufA))[:m]
+			copy(sa[w*wordSize:], a[w*wordSize:m])
+		}
+
+		switch {
+		case &b[0] == &a[0]:
+			sb = sa
+		case wordOffset(b) != 0 && w > 0:
+			realign(bufB[:w], b)
+			sb = (*[realignWords * wordSize]byte)(unsafe.Pointer(&bufB))[:m]
+			copy(sb[w*wordSize:], b[w*wordSize:m])
+		}
+
+		fast(dst[:m], sa, sb)
+		dst, a, b = dst[m:], a[m:], b[m:]
+	}
+
+	return n
+}
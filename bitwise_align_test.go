@@ -0,0 +1,81 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"unsafe"
+)
+
+func TestRealign(t *testing.T) {
+	src := make([]byte, 20*wordSize)
+	rand.Read(src)
+
+	for off := 0; off < wordSize; off++ {
+		for w := 0; w < 16; w++ {
+			for extra := 0; extra < wordSize; extra++ {
+				s := src[off : off+w*wordSize+extra]
+
+				dst := make([]uintptr, w)
+				realign(dst, s)
+
+				var got []byte
+				if w > 0 {
+					got = (*[16 * wordSize]byte)(unsafe.Pointer(&dst[0]))[:w*wordSize]
+				}
+
+				if !bytes.Equal(got, s[:w*wordSize]) {
+					t.Errorf("realign failed for offset %d, %d words and %d extra bytes", off, w, extra)
+				}
+			}
+		}
+	}
+}
+
+func TestAlignedBytes(t *testing.T) {
+	for _, op := range []struct {
+		name       string
+		fast, safe func(dst, a, b []byte) int
+	}{
+		{"XOR", fastXORBytes, safeXORBytes},
+		{"XNOR", fastXNORBytes, safeXNORBytes},
+		{"And", fastAndBytes, safeAndBytes},
+		{"AndNot", fastAndNotBytes, safeAndNotBytes},
+		{"NotAnd", fastNotAndBytes, safeNotAndBytes},
+		{"Or", fastOrBytes, safeOrBytes},
+		{"NotOr", fastNotOrBytes, safeNotOrBytes},
+		{"Not", func(dst, src, _ []byte) int {
+			return fastNotBytes(dst, src)
+		}, testNotBytes},
+	} {
+		for _, size := range []int{0, 1, 7, 8, 63, 500, realignWords*wordSize + 13} {
+			for alignD := 0; alignD < wordSize; alignD++ {
+				for alignA := 0; alignA < wordSize; alignA++ {
+					for alignB := 0; alignB < wordSize; alignB += 3 {
+						a := make([]byte, size+alignA)[alignA:]
+						rand.Read(a)
+
+						b := make([]byte, size+alignB)[alignB:]
+						rand.Read(b)
+
+						d1 := make([]byte, size+alignD)[alignD:]
+						alignedBytes(d1, a, b, op.fast, op.safe)
+
+						d2 := make([]byte, size)
+						op.safe(d2, a, b)
+
+						if !bytes.Equal(d1, d2) {
+							t.Errorf("%s failed for size %d with alignment (%d, %d, %d)",
+								op.name, size, alignD, alignA, alignB)
+						}
+					}
+				}
+			}
+		}
+	}
+}
@@ -3,7 +3,7 @@
 // Modified BSD License license that can be found in
 // the LICENSE file.
 
-// +build amd64,!gccgo,!appengine
+// +build amd64,!gccgo,!appengine,!purego
 
 // Package bitwise provides efficient implementations of xor/xnor/and/and-not/nand/or/nor/not.
 package bitwise
@@ -22,7 +22,19 @@ func XOR(dst, a, b []byte) int {
 		return 0
 	}
 
-	xorASM(&dst[0], &a[0], &b[0], uint64(n))
+	checkOverlap(dst, a, b)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		xorAVX512(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		xorAVX2(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implSSE2:
+		xorASM(&dst[0], &a[0], &b[0], uint64(n))
+	default:
+		fastXORBytes(dst, a, b)
+	}
+
 	return n
 }
 
@@ -40,7 +52,19 @@ func XNOR(dst, a, b []byte) int {
 		return 0
 	}
 
-	xnorASM(&dst[0], &a[0], &b[0], uint64(n))
+	checkOverlap(dst, a, b)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		xnorAVX512(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		xnorAVX2(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implSSE2:
+		xnorASM(&dst[0], &a[0], &b[0], uint64(n))
+	default:
+		fastXNORBytes(dst, a, b)
+	}
+
 	return n
 }
 
@@ -58,7 +82,19 @@ func And(dst, a, b []byte) int {
 		return 0
 	}
 
-	andASM(&dst[0], &a[0], &b[0], uint64(n))
+	checkOverlap(dst, a, b)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		andAVX512(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		andAVX2(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implSSE2:
+		andASM(&dst[0], &a[0], &b[0], uint64(n))
+	default:
+		fastAndBytes(dst, a, b)
+	}
+
 	return n
 }
 
@@ -76,7 +112,19 @@ func AndNot(dst, a, b []byte) int {
 		return 0
 	}
 
-	andNotASM(&dst[0], &a[0], &b[0], uint64(n))
+	checkOverlap(dst, a, b)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		andNotAVX512(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		andNotAVX2(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implSSE2:
+		andNotASM(&dst[0], &a[0], &b[0], uint64(n))
+	default:
+		fastAndNotBytes(dst, a, b)
+	}
+
 	return n
 }
 
@@ -94,7 +142,19 @@ func NotAnd(dst, a, b []byte) int {
 		return 0
 	}
 
-	nandASM(&dst[0], &a[0], &b[0], uint64(n))
+	checkOverlap(dst, a, b)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		nandAVX512(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		nandAVX2(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implSSE2:
+		nandASM(&dst[0], &a[0], &b[0], uint64(n))
+	default:
+		fastNotAndBytes(dst, a, b)
+	}
+
 	return n
 }
 
@@ -112,7 +172,19 @@ func Or(dst, a, b []byte) int {
 		return 0
 	}
 
-	orASM(&dst[0], &a[0], &b[0], uint64(n))
+	checkOverlap(dst, a, b)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		orAVX512(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		orAVX2(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implSSE2:
+		orASM(&dst[0], &a[0], &b[0], uint64(n))
+	default:
+		fastOrBytes(dst, a, b)
+	}
+
 	return n
 }
 
@@ -130,7 +202,19 @@ func NotOr(dst, a, b []byte) int {
 		return 0
 	}
 
-	norASM(&dst[0], &a[0], &b[0], uint64(n))
+	checkOverlap(dst, a, b)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		norAVX512(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		norAVX2(&dst[0], &a[0], &b[0], uint64(n))
+	case impl >= implSSE2:
+		norASM(&dst[0], &a[0], &b[0], uint64(n))
+	default:
+		fastNotOrBytes(dst, a, b)
+	}
+
 	return n
 }
 
@@ -145,40 +229,161 @@ func Not(dst, src []byte) int {
 		return 0
 	}
 
-	notASM(&dst[0], &src[0], uint64(n))
+	checkOverlap(dst, src, src)
+
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		notAVX512(&dst[0], &src[0], uint64(n))
+	case impl >= implAVX2 && n >= 32:
+		notAVX2(&dst[0], &src[0], uint64(n))
+	case impl >= implSSE2:
+		notASM(&dst[0], &src[0], uint64(n))
+	default:
+		fastNotBytes(dst, src)
+	}
+
 	return n
 }
 
+// The amd64 tiers are ordered so that each supports every kernel of the
+// tiers below it, dispatch compares them with >=.
+const (
+	implGeneric implementation = iota
+	implSSE2
+	implAVX2
+	implAVX512
+)
+
+var implNames = [...]string{
+	implGeneric: "generic",
+	implSSE2:    "sse2",
+	implAVX2:    "avx2",
+	implAVX512:  "avx512",
+}
+
+func (i implementation) supported() bool {
+	switch i {
+	case implGeneric, implSSE2:
+		return true
+	case implAVX2:
+		return hasAVX2
+	case implAVX512:
+		return hasAVX2 && hasAVX512
+	default:
+		return false
+	}
+}
+
 //go:generate go run asm_gen.go
 
 // This function is implemented in bitwise_xor_amd64.s
 //go:noescape
 func xorASM(dst, a, b *byte, len uint64)
 
+// This function is implemented in bitwise_xor_amd64.s
+// len must be at least 32.
+//go:noescape
+func xorAVX2(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_xor_amd64.s
+// len must be at least 64.
+//go:noescape
+func xorAVX512(dst, a, b *byte, len uint64)
+
 // This function is implemented in bitwise_xnor_amd64.s
 //go:noescape
 func xnorASM(dst, a, b *byte, len uint64)
 
+// This function is implemented in bitwise_xnor_amd64.s
+// len must be at least 32.
+//go:noescape
+func xnorAVX2(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_xnor_amd64.s
+// len must be at least 64.
+//go:noescape
+func xnorAVX512(dst, a, b *byte, len uint64)
+
 // This function is implemented in bitwise_and_amd64.s
 //go:noescape
 func andASM(dst, a, b *byte, len uint64)
 
+// This function is implemented in bitwise_and_amd64.s
+// len must be at least 32.
+//go:noescape
+func andAVX2(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_and_amd64.s
+// len must be at least 64.
+//go:noescape
+func andAVX512(dst, a, b *byte, len uint64)
+
 // This function is implemented in bitwise_andnot_amd64.s
 //go:noescape
 func andNotASM(dst, a, b *byte, len uint64)
 
+// This function is implemented in bitwise_andnot_amd64.s
+// len must be at least 32.
+//go:noescape
+func andNotAVX2(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_andnot_amd64.s
+// len must be at least 64.
+//go:noescape
+func andNotAVX512(dst, a, b *byte, len uint64)
+
 // This function is implemented in bitwise_nand_amd64.s
 //go:noescape
 func nandASM(dst, a, b *byte, len uint64)
 
+// This function is implemented in bitwise_nand_amd64.s
+// len must be at least 32.
+//go:noescape
+func nandAVX2(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_nand_amd64.s
+// len must be at least 64.
+//go:noescape
+func nandAVX512(dst, a, b *byte, len uint64)
+
 // This function is implemented in bitwise_or_amd64.s
 //go:noescape
 func orASM(dst, a, b *byte, len uint64)
 
+// This function is implemented in bitwise_or_amd64.s
+// len must be at least 32.
+//go:noescape
+func orAVX2(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_or_amd64.s
+// len must be at least 64.
+//go:noescape
+func orAVX512(dst, a, b *byte, len uint64)
+
 // This function is implemented in bitwise_nor_amd64.s
 //go:noescape
 func norASM(dst, a, b *byte, len uint64)
 
+// This function is implemented in bitwise_nor_amd64.s
+// len must be at least 32.
+//go:noescape
+func norAVX2(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_nor_amd64.s
+// len must be at least 64.
+//go:noescape
+func norAVX512(dst, a, b *byte, len uint64)
+
 // This function is implemented in bitwise_not_amd64.s
 //go:noescape
 func notASM(dst, src *byte, len uint64)
+
+// This function is implemented in bitwise_not_amd64.s
+// len must be at least 32.
+//go:noescape
+func notAVX2(dst, src *byte, len uint64)
+
+// This function is implemented in bitwise_not_amd64.s
+// len must be at least 64.
+//go:noescape
+func notAVX512(dst, src *byte, len uint64)
@@ -0,0 +1,254 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build arm64,!gccgo,!appengine,!purego
+
+// Package bitwise provides efficient implementations of xor/xnor/and/and-not/nand/or/nor/not.
+package bitwise
+
+// XOR sets each element in according to dst[i] = a[i] XOR b[i]
+func XOR(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, a, b)
+
+	if impl == implNEON {
+		xorNEON(&dst[0], &a[0], &b[0], uint64(n))
+	} else {
+		fastXORBytes(dst, a, b)
+	}
+
+	return n
+}
+
+// XNOR sets each element in according to dst[i] = NOT (a[i] XOR b[i])
+func XNOR(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, a, b)
+
+	if impl == implNEON {
+		xnorNEON(&dst[0], &a[0], &b[0], uint64(n))
+	} else {
+		fastXNORBytes(dst, a, b)
+	}
+
+	return n
+}
+
+// And sets each element in according to dst[i] = a[i] AND b[i]
+func And(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, a, b)
+
+	if impl == implNEON {
+		andNEON(&dst[0], &a[0], &b[0], uint64(n))
+	} else {
+		fastAndBytes(dst, a, b)
+	}
+
+	return n
+}
+
+// AndNot sets each element in according to dst[i] = a[i] AND (NOT b[i])
+func AndNot(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, a, b)
+
+	if impl == implNEON {
+		andNotNEON(&dst[0], &a[0], &b[0], uint64(n))
+	} else {
+		fastAndNotBytes(dst, a, b)
+	}
+
+	return n
+}
+
+// NotAnd sets each element in according to dst[i] = NOT (a[i] AND b[i])
+func NotAnd(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, a, b)
+
+	if impl == implNEON {
+		nandNEON(&dst[0], &a[0], &b[0], uint64(n))
+	} else {
+		fastNotAndBytes(dst, a, b)
+	}
+
+	return n
+}
+
+// Or sets each element in according to dst[i] = a[i] OR b[i]
+func Or(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, a, b)
+
+	if impl == implNEON {
+		orNEON(&dst[0], &a[0], &b[0], uint64(n))
+	} else {
+		fastOrBytes(dst, a, b)
+	}
+
+	return n
+}
+
+// NotOr sets each element in according to dst[i] = NOT (a[i] OR b[i])
+func NotOr(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, a, b)
+
+	if impl == implNEON {
+		norNEON(&dst[0], &a[0], &b[0], uint64(n))
+	} else {
+		fastNotOrBytes(dst, a, b)
+	}
+
+	return n
+}
+
+// Not sets each element in according to dst[i] = NOT src[i]
+func Not(dst, src []byte) int {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst, src, src)
+
+	if impl == implNEON {
+		notNEON(&dst[0], &src[0], uint64(n))
+	} else {
+		fastNotBytes(dst, src)
+	}
+
+	return n
+}
+
+const (
+	implGeneric implementation = iota
+	implNEON
+)
+
+var implNames = [...]string{
+	implGeneric: "generic",
+	implNEON:    "neon",
+}
+
+func (i implementation) supported() bool {
+	return i == implGeneric || i == implNEON
+}
+
+//go:generate go run asm_gen_arm64.go
+
+// This function is implemented in bitwise_xor_arm64.s
+//go:noescape
+func xorNEON(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_xnor_arm64.s
+//go:noescape
+func xnorNEON(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_and_arm64.s
+//go:noescape
+func andNEON(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_andnot_arm64.s
+//go:noescape
+func andNotNEON(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_nand_arm64.s
+//go:noescape
+func nandNEON(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_or_arm64.s
+//go:noescape
+func orNEON(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_nor_arm64.s
+//go:noescape
+func norNEON(dst, a, b *byte, len uint64)
+
+// This function is implemented in bitwise_not_arm64.s
+//go:noescape
+func notNEON(dst, src *byte, len uint64)
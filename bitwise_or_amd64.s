@@ -5,7 +5,7 @@
 //
 // This file is auto-generated - do not modify
 
-// +build amd64,!gccgo,!appengine
+// +build amd64,!gccgo,!appengine,!purego
 
 #include "textflag.h"
 
@@ -15,41 +15,105 @@ TEXT ·orASM(SB),NOSPLIT,$0
 	MOVQ b+16(FP), DX
 	MOVQ len+24(FP), BX
 	CMPQ BX, $16
-	JB loop
+	JB small
+	LEAQ (DI)(BX*1), R8
+	MOVOU (SI), X8
+	MOVOU (DX), X9
+	POR X8, X9
+	MOVOU -16(SI)(BX*1), X10
+	MOVOU -16(DX)(BX*1), X11
+	POR X10, X11
+	MOVQ R8, CX
+	ANDQ $15, CX
+	SUBQ CX, BX
+	CMPQ BX, $4194304
+	JAE ntloop
 	CMPQ BX, $64
-	JB bigloop
+	JB big
 hugeloop:
 	MOVOU -16(SI)(BX*1), X0
-	MOVOU -32(SI)(BX*1), X2
-	MOVOU -48(SI)(BX*1), X4
-	MOVOU -64(SI)(BX*1), X6
 	MOVOU -16(DX)(BX*1), X1
-	MOVOU -32(DX)(BX*1), X3
-	MOVOU -48(DX)(BX*1), X5
-	MOVOU -64(DX)(BX*1), X7
 	POR X0, X1
+	MOVOU -32(SI)(BX*1), X2
+	MOVOU -32(DX)(BX*1), X3
 	POR X2, X3
+	MOVOU -48(SI)(BX*1), X4
+	MOVOU -48(DX)(BX*1), X5
 	POR X4, X5
+	MOVOU -64(SI)(BX*1), X6
+	MOVOU -64(DX)(BX*1), X7
 	POR X6, X7
-	MOVOU X1, -16(DI)(BX*1)
-	MOVOU X3, -32(DI)(BX*1)
-	MOVOU X5, -48(DI)(BX*1)
-	MOVOU X7, -64(DI)(BX*1)
+	MOVO X1, -16(DI)(BX*1)
+	MOVO X3, -32(DI)(BX*1)
+	MOVO X5, -48(DI)(BX*1)
+	MOVO X7, -64(DI)(BX*1)
 	SUBQ $64, BX
-	JZ ret
 	CMPQ BX, $64
 	JAE hugeloop
+big:
 	CMPQ BX, $16
-	JB loop
+	JB done
 bigloop:
 	MOVOU -16(SI)(BX*1), X0
 	MOVOU -16(DX)(BX*1), X1
 	POR X0, X1
-	MOVOU X1, -16(DI)(BX*1)
+	MOVO X1, -16(DI)(BX*1)
 	SUBQ $16, BX
-	JZ ret
 	CMPQ BX, $16
 	JAE bigloop
+done:
+	MOVOU X11, -16(R8)
+	MOVOU X9, (DI)
+	RET
+ntloop:
+	MOVOU -16(SI)(BX*1), X0
+	MOVOU -16(DX)(BX*1), X1
+	POR X0, X1
+	MOVOU -32(SI)(BX*1), X2
+	MOVOU -32(DX)(BX*1), X3
+	POR X2, X3
+	MOVOU -48(SI)(BX*1), X4
+	MOVOU -48(DX)(BX*1), X5
+	POR X4, X5
+	MOVOU -64(SI)(BX*1), X6
+	MOVOU -64(DX)(BX*1), X7
+	POR X6, X7
+	MOVNTO X1, -16(DI)(BX*1)
+	MOVNTO X3, -32(DI)(BX*1)
+	MOVNTO X5, -48(DI)(BX*1)
+	MOVNTO X7, -64(DI)(BX*1)
+	SUBQ $64, BX
+	CMPQ BX, $64
+	JAE ntloop
+	SFENCE
+	JMP big
+small:
+	CMPQ BX, $8
+	JB word
+	MOVQ (SI), X8
+	MOVQ (DX), X9
+	POR X8, X9
+	MOVQ -8(SI)(BX*1), X10
+	MOVQ -8(DX)(BX*1), X11
+	POR X10, X11
+	MOVQ X9, (DI)
+	MOVQ X11, -8(DI)(BX*1)
+	RET
+word:
+	CMPQ BX, $4
+	JB loop_check
+	MOVL (SI), X8
+	MOVL (DX), X9
+	POR X8, X9
+	MOVL -4(SI)(BX*1), X10
+	MOVL -4(DX)(BX*1), X11
+	POR X10, X11
+	MOVL X9, (DI)
+	MOVL X11, -4(DI)(BX*1)
+	RET
+loop_check:
+	TESTQ BX, BX
+	JZ ret
 loop:
 	MOVB -1(SI)(BX*1), AX
 	ORB -1(DX)(BX*1), AX
@@ -58,3 +122,159 @@ loop:
 	JNZ loop
 ret:
 	RET
+
+TEXT ·orAVX2(SB),NOSPLIT,$0
+	MOVQ dst+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DX
+	MOVQ len+24(FP), BX
+	LEAQ (DI)(BX*1), R8
+	VMOVDQU (SI), Y8
+	VMOVDQU (DX), Y9
+	VPOR Y8, Y9, Y9
+	VMOVDQU -32(SI)(BX*1), Y10
+	VMOVDQU -32(DX)(BX*1), Y11
+	VPOR Y10, Y11, Y11
+	MOVQ R8, CX
+	ANDQ $31, CX
+	SUBQ CX, BX
+	CMPQ BX, $4194304
+	JAE ntloop
+	CMPQ BX, $128
+	JB big
+hugeloop:
+	VMOVDQU -32(SI)(BX*1), Y0
+	VMOVDQU -32(DX)(BX*1), Y1
+	VPOR Y0, Y1, Y1
+	VMOVDQU -64(SI)(BX*1), Y2
+	VMOVDQU -64(DX)(BX*1), Y3
+	VPOR Y2, Y3, Y3
+	VMOVDQU -96(SI)(BX*1), Y4
+	VMOVDQU -96(DX)(BX*1), Y5
+	VPOR Y4, Y5, Y5
+	VMOVDQU -128(SI)(BX*1), Y6
+	VMOVDQU -128(DX)(BX*1), Y7
+	VPOR Y6, Y7, Y7
+	VMOVDQA Y1, -32(DI)(BX*1)
+	VMOVDQA Y3, -64(DI)(BX*1)
+	VMOVDQA Y5, -96(DI)(BX*1)
+	VMOVDQA Y7, -128(DI)(BX*1)
+	SUBQ $128, BX
+	CMPQ BX, $128
+	JAE hugeloop
+big:
+	CMPQ BX, $32
+	JB done
+bigloop:
+	VMOVDQU -32(SI)(BX*1), Y0
+	VMOVDQU -32(DX)(BX*1), Y1
+	VPOR Y0, Y1, Y1
+	VMOVDQA Y1, -32(DI)(BX*1)
+	SUBQ $32, BX
+	CMPQ BX, $32
+	JAE bigloop
+done:
+	VMOVDQU Y11, -32(R8)
+	VMOVDQU Y9, (DI)
+	VZEROUPPER
+	RET
+ntloop:
+	VMOVDQU -32(SI)(BX*1), Y0
+	VMOVDQU -32(DX)(BX*1), Y1
+	VPOR Y0, Y1, Y1
+	VMOVDQU -64(SI)(BX*1), Y2
+	VMOVDQU -64(DX)(BX*1), Y3
+	VPOR Y2, Y3, Y3
+	VMOVDQU -96(SI)(BX*1), Y4
+	VMOVDQU -96(DX)(BX*1), Y5
+	VPOR Y4, Y5, Y5
+	VMOVDQU -128(SI)(BX*1), Y6
+	VMOVDQU -128(DX)(BX*1), Y7
+	VPOR Y6, Y7, Y7
+	VMOVNTDQ Y1, -32(DI)(BX*1)
+	VMOVNTDQ Y3, -64(DI)(BX*1)
+	VMOVNTDQ Y5, -96(DI)(BX*1)
+	VMOVNTDQ Y7, -128(DI)(BX*1)
+	SUBQ $128, BX
+	CMPQ BX, $128
+	JAE ntloop
+	SFENCE
+	JMP big
+
+TEXT ·orAVX512(SB),NOSPLIT,$0
+	MOVQ dst+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DX
+	MOVQ len+24(FP), BX
+	LEAQ (DI)(BX*1), R8
+	VMOVDQU64 (SI), Z8
+	VMOVDQU64 (DX), Z9
+	VPTERNLOGD $252, Z8, Z8, Z9
+	VMOVDQU64 -64(SI)(BX*1), Z10
+	VMOVDQU64 -64(DX)(BX*1), Z11
+	VPTERNLOGD $252, Z10, Z10, Z11
+	MOVQ R8, CX
+	ANDQ $63, CX
+	SUBQ CX, BX
+	CMPQ BX, $4194304
+	JAE ntloop
+	CMPQ BX, $256
+	JB big
+hugeloop:
+	VMOVDQU64 -64(SI)(BX*1), Z0
+	VMOVDQU64 -64(DX)(BX*1), Z1
+	VPTERNLOGD $252, Z0, Z0, Z1
+	VMOVDQU64 -128(SI)(BX*1), Z2
+	VMOVDQU64 -128(DX)(BX*1), Z3
+	VPTERNLOGD $252, Z2, Z2, Z3
+	VMOVDQU64 -192(SI)(BX*1), Z4
+	VMOVDQU64 -192(DX)(BX*1), Z5
+	VPTERNLOGD $252, Z4, Z4, Z5
+	VMOVDQU64 -256(SI)(BX*1), Z6
+	VMOVDQU64 -256(DX)(BX*1), Z7
+	VPTERNLOGD $252, Z6, Z6, Z7
+	VMOVDQA64 Z1, -64(DI)(BX*1)
+	VMOVDQA64 Z3, -128(DI)(BX*1)
+	VMOVDQA64 Z5, -192(DI)(BX*1)
+	VMOVDQA64 Z7, -256(DI)(BX*1)
+	SUBQ $256, BX
+	CMPQ BX, $256
+	JAE hugeloop
+big:
+	CMPQ BX, $64
+	JB done
+bigloop:
+	VMOVDQU64 -64(SI)(BX*1), Z0
+	VMOVDQU64 -64(DX)(BX*1), Z1
+	VPTERNLOGD $252, Z0, Z0, Z1
+	VMOVDQA64 Z1, -64(DI)(BX*1)
+	SUBQ $64, BX
+	CMPQ BX, $64
+	JAE bigloop
+done:
+	VMOVDQU64 Z11, -64(R8)
+	VMOVDQU64 Z9, (DI)
+	VZEROUPPER
+	RET
+ntloop:
+	VMOVDQU64 -64(SI)(BX*1), Z0
+	VMOVDQU64 -64(DX)(BX*1), Z1
+	VPTERNLOGD $252, Z0, Z0, Z1
+	VMOVDQU64 -128(SI)(BX*1), Z2
+	VMOVDQU64 -128(DX)(BX*1), Z3
+	VPTERNLOGD $252, Z2, Z2, Z3
+	VMOVDQU64 -192(SI)(BX*1), Z4
+	VMOVDQU64 -192(DX)(BX*1), Z5
+	VPTERNLOGD $252, Z4, Z4, Z5
+	VMOVDQU64 -256(SI)(BX*1), Z6
+	VMOVDQU64 -256(DX)(BX*1), Z7
+	VPTERNLOGD $252, Z6, Z6, Z7
+	VMOVNTDQ Z1, -64(DI)(BX*1)
+	VMOVNTDQ Z3, -128(DI)(BX*1)
+	VMOVNTDQ Z5, -192(DI)(BX*1)
+	VMOVNTDQ Z7, -256(DI)(BX*1)
+	SUBQ $256, BX
+	CMPQ BX, $256
+	JAE ntloop
+	SFENCE
+	JMP big
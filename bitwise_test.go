@@ -392,6 +392,46 @@ func testThree(t *testing.T, fn, testFn func(dst, a, b []byte) int, testVectors
 		}
 	}
 
+	for size := 0; size < 300; size++ {
+		for align := 0; align < 16; align += 5 {
+			p := make([]byte, size+align)[align:]
+			rand.Read(p)
+
+			q := make([]byte, size)
+			rand.Read(q)
+
+			d := make([]byte, size)
+			testFn(d, p, q)
+
+			fn(p, p, q)
+
+			if !bytes.Equal(d, p) {
+				t.Errorf("not equal in place with size %d and alignment %d", size, align)
+			}
+		}
+	}
+
+	// Large enough to use the non-temporal stores on amd64.
+	const large = 4*1024*1024 + 77
+
+	for _, alignD := range []int{0, 1, 15} {
+		p := make([]byte, large)
+		rand.Read(p)
+
+		q := make([]byte, large)
+		rand.Read(q)
+
+		d1 := make([]byte, large+alignD)[alignD:]
+		fn(d1, p, q)
+
+		d2 := make([]byte, large+alignD)[alignD:]
+		testFn(d2, p, q)
+
+		if !bytes.Equal(d1, d2) {
+			t.Errorf("not equal for large buffer with alignment %d", alignD)
+		}
+	}
+
 	if err := quick.CheckEqual(func(dst, a, b []byte) []byte {
 		d1 := append([]byte{}, dst...)
 		testFn(d1, a, b)
@@ -406,36 +446,197 @@ func testThree(t *testing.T, fn, testFn func(dst, a, b []byte) int, testVectors
 	}
 }
 
+// testImplementations runs fn against each implementation supported by
+// the CPU.
+func testImplementations(t *testing.T, fn func(t *testing.T)) {
+	defer SetImplementation(Implementation())
+
+	for _, name := range implNames {
+		t.Run(name, func(t *testing.T) {
+			if err := SetImplementation(name); err != nil {
+				t.Skip(err)
+			}
+
+			fn(t)
+		})
+	}
+}
+
 func TestXOR(t *testing.T) {
-	testThree(t, XOR, testXORBytes, xorTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, XOR, testXORBytes, xorTestVectors)
+	})
 }
 
 func TestXNOR(t *testing.T) {
-	testThree(t, XNOR, testXNORBytes, xnorTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, XNOR, testXNORBytes, xnorTestVectors)
+	})
 }
 
 func TestAnd(t *testing.T) {
-	testThree(t, And, testAndBytes, andTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, And, testAndBytes, andTestVectors)
+	})
 }
 
 func TestAndNot(t *testing.T) {
-	testThree(t, AndNot, testAndNotBytes, andNotTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, AndNot, testAndNotBytes, andNotTestVectors)
+	})
 }
 
 func TestNotAnd(t *testing.T) {
-	testThree(t, NotAnd, testNotAndBytes, nandTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, NotAnd, testNotAndBytes, nandTestVectors)
+	})
 }
 
 func TestOr(t *testing.T) {
-	testThree(t, Or, testOrBytes, orTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, Or, testOrBytes, orTestVectors)
+	})
 }
 
 func TestNotOr(t *testing.T) {
-	testThree(t, NotOr, testNotOrBytes, norTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, NotOr, testNotOrBytes, norTestVectors)
+	})
 }
 
 func TestNot(t *testing.T) {
-	testThree(t, testNotThree, testNotBytes, notTestVectors)
+	testImplementations(t, func(t *testing.T) {
+		testThree(t, testNotThree, testNotBytes, notTestVectors)
+	})
+}
+
+func TestOverlaps(t *testing.T) {
+	buf := make([]byte, 16)
+
+	for _, tc := range []struct {
+		x, y []byte
+		want bool
+	}{
+		{buf[:8], buf[8:], false},
+		{buf[:9], buf[8:], true},
+		{buf[4:], buf[:5], true},
+		{buf, buf, true},
+		{buf[:0], buf, false},
+		{buf, make([]byte, 16), false},
+	} {
+		if got := Overlaps(tc.x, tc.y); got != tc.want {
+			t.Errorf("Overlaps(buf[%d:%d], buf[%d:%d]) = %t",
+				cap(buf)-cap(tc.x), cap(buf)-cap(tc.x)+len(tc.x),
+				cap(buf)-cap(tc.y), cap(buf)-cap(tc.y)+len(tc.y), got)
+		}
+	}
+}
+
+func testPanics(fn func()) (panicked bool) {
+	defer func() {
+		panicked = recover() != nil
+	}()
+
+	fn()
+	return false
+}
+
+// TestOverlap checks that every implementation accepts dst being the
+// same as a source and rejects any other overlap.
+func TestOverlap(t *testing.T) {
+	for _, op := range []struct {
+		name       string
+		fn, testFn func(dst, a, b []byte) int
+	}{
+		{"XOR", XOR, testXORBytes},
+		{"XNOR", XNOR, testXNORBytes},
+		{"And", And, testAndBytes},
+		{"AndNot", AndNot, testAndNotBytes},
+		{"NotAnd", NotAnd, testNotAndBytes},
+		{"Or", Or, testOrBytes},
+		{"NotOr", NotOr, testNotOrBytes},
+		{"Not", testNotThree, testNotBytes},
+		{"ParallelXOR", testParallelOpts.XOR, testXORBytes},
+		{"ParallelAnd", testParallelOpts.And, testAndBytes},
+		{"ParallelNot", func(dst, src, _ []byte) int {
+			return testParallelOpts.Not(dst, src)
+		}, testNotBytes},
+	} {
+		op := op
+		t.Run(op.name, func(t *testing.T) {
+			testImplementations(t, func(t *testing.T) {
+				for _, size := range []int{2, 15, 100, 1000, 1 << 16} {
+					a := make([]byte, size)
+					rand.Read(a)
+
+					b := make([]byte, size)
+					rand.Read(b)
+
+					want := make([]byte, size)
+					op.testFn(want, a, b)
+
+					op.fn(b, a, b)
+
+					if !bytes.Equal(b, want) {
+						t.Errorf("not equal with dst the same as b for size %d", size)
+					}
+
+					buf := make([]byte, 2*size)
+					if !testPanics(func() {
+						op.fn(buf[1:size+1], buf[:size], a)
+					}) {
+						t.Errorf("no panic for partial overlap of size %d", size)
+					}
+
+					// With Parallel, no single chunk overlaps when dst is
+					// offset from the source by more than a chunk.
+					if !testPanics(func() {
+						op.fn(buf[size/2:size/2+size], buf[:size], a)
+					}) {
+						t.Errorf("no panic for overlap of size %d offset by %d", size, size/2)
+					}
+				}
+			})
+		})
+	}
+}
+
+// TestAgainstGeneric compares each implementation against the portable
+// Go code, which is built on every platform and with the purego tag.
+func TestAgainstGeneric(t *testing.T) {
+	for _, op := range []struct {
+		name        string
+		fn, generic func(dst, a, b []byte) int
+	}{
+		{"XOR", XOR, safeXORBytes},
+		{"XNOR", XNOR, safeXNORBytes},
+		{"And", And, safeAndBytes},
+		{"AndNot", AndNot, safeAndNotBytes},
+		{"NotAnd", NotAnd, safeNotAndBytes},
+		{"Or", Or, safeOrBytes},
+		{"NotOr", NotOr, safeNotOrBytes},
+		{"Not", testNotThree, func(dst, src, _ []byte) int {
+			return safeNotBytes(dst, src)
+		}},
+	} {
+		op := op
+		t.Run(op.name, func(t *testing.T) {
+			testImplementations(t, func(t *testing.T) {
+				if err := quick.CheckEqual(func(dst, a, b []byte) []byte {
+					d := append([]byte{}, dst...)
+					op.generic(d, a, b)
+					return d
+				}, func(dst, a, b []byte) []byte {
+					op.fn(dst, a, b)
+					return dst
+				}, &quick.Config{
+					MaxCountScale: 50,
+				}); err != nil {
+					t.Error(err)
+				}
+			})
+		})
+	}
 }
 
 var benchSizes = []struct {
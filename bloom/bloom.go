@@ -0,0 +1,201 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// Package bloom implements Bloom filters that can be merged and
+// intersected using the bitwise package.
+//
+// The hashing and binary encoding are stable, filters with the same
+// parameters built in different processes may be combined.
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math"
+
+	"github.com/tmthrgd/go-bitwise"
+)
+
+// ErrIncompatible is returned when combining filters that were created
+// with different parameters.
+var ErrIncompatible = errors.New("bloom: filters have different parameters")
+
+// maxK is the largest number of hash functions a Filter may use. It is
+// far beyond any useful value, even a false positive rate of 1e-300 needs
+// fewer than 1000, and bounds the work of Add and Test for decoded
+// filters.
+const maxK = 1 << 10
+
+// A Filter is a Bloom filter.
+type Filter struct {
+	m    uint64
+	k    uint32
+	bits []byte
+}
+
+// New returns a Filter with m bits and k hash functions. m is rounded
+// up to a multiple of 8. k must be between 1 and 1024.
+func New(m uint64, k uint) *Filter {
+	if m == 0 || k == 0 || k > maxK {
+		panic("bloom: invalid parameters")
+	}
+
+	m = (m + 7) &^ 7
+
+	return &Filter{
+		m:    m,
+		k:    uint32(k),
+		bits: make([]byte, m/8),
+	}
+}
+
+// NewWithEstimates returns a Filter sized to hold n elements with a
+// false positive rate of approximately p.
+func NewWithEstimates(n uint64, p float64) *Filter {
+	if n == 0 || p <= 0 || p >= 1 {
+		panic("bloom: invalid estimates")
+	}
+
+	m := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	k := math.Min(maxK, math.Max(1, math.Round(m/float64(n)*math.Ln2)))
+	return New(uint64(m), uint(k))
+}
+
+// Cap returns the number of bits in the filter.
+func (f *Filter) Cap() uint64 {
+	return f.m
+}
+
+// K returns the number of hash functions used by the filter.
+func (f *Filter) K() uint {
+	return uint(f.k)
+}
+
+// hash returns the two hashes used to derive the k bit positions
+// for data. It must not change as it is part of the encoding.
+func hash(data []byte) (h1, h2 uint64) {
+	h := fnv.New64a()
+	h.Write(data)
+	h1 = h.Sum64()
+
+	// The second hash is the splitmix64 finaliser of the first, it
+	// is made odd so that it is never zero.
+	h2 = h1
+	h2 = (h2 ^ h2>>30) * 0xbf58476d1ce4e5b9
+	h2 = (h2 ^ h2>>27) * 0x94d049bb133111eb
+	h2 ^= h2 >> 31
+	return h1, h2 | 1
+}
+
+// Add adds data to the filter.
+func (f *Filter) Add(data []byte) {
+	h1, h2 := hash(data)
+
+	for i := uint64(0); i < uint64(f.k); i++ {
+		b := (h1 + i*h2) % f.m
+		f.bits[b/8] |= 1 << (b % 8)
+	}
+}
+
+// Test reports whether data may have been added to the filter. It
+// never returns false for data that has been added.
+func (f *Filter) Test(data []byte) bool {
+	h1, h2 := hash(data)
+
+	for i := uint64(0); i < uint64(f.k); i++ {
+		b := (h1 + i*h2) % f.m
+		if f.bits[b/8]&(1<<(b%8)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *Filter) compatible(g *Filter) bool {
+	return f.m == g.m && f.k == g.k
+}
+
+// Union sets f to the union of f and g, afterwards f tests true for
+// any data added to either filter.
+func (f *Filter) Union(g *Filter) error {
+	if !f.compatible(g) {
+		return ErrIncompatible
+	}
+
+	bitwise.Or(f.bits, f.bits, g.bits)
+	return nil
+}
+
+// Intersect sets f to the intersection of f and g, afterwards f tests
+// true for any data added to both filters.
+func (f *Filter) Intersect(g *Filter) error {
+	if !f.compatible(g) {
+		return ErrIncompatible
+	}
+
+	bitwise.And(f.bits, f.bits, g.bits)
+	return nil
+}
+
+// Reset removes all data from the filter.
+func (f *Filter) Reset() {
+	for i := range f.bits {
+		f.bits[i] = 0
+	}
+}
+
+// OnesCount returns the number of bits set in the filter.
+func (f *Filter) OnesCount() uint64 {
+	return bitwise.OnesCount(f.bits)
+}
+
+// EstimatedCount returns an estimate of the number of distinct
+// elements added to the filter.
+func (f *Filter) EstimatedCount() uint64 {
+	x := float64(f.OnesCount())
+	if x == float64(f.m) {
+		return math.MaxUint64
+	}
+
+	m, k := float64(f.m), float64(f.k)
+	return uint64(math.Round(-m / k * math.Log1p(-x/m)))
+}
+
+// The binary encoding consists of a 4 byte magic number, ending with
+// the version, followed by k as a big-endian uint32, m as a big-endian
+// uint64 and the m/8 bytes of the filter.
+const headerSize = 4 + 4 + 8
+
+var magic = [4]byte{'b', 'l', 'm', 1}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	b := make([]byte, headerSize+len(f.bits))
+	copy(b, magic[:])
+	binary.BigEndian.PutUint32(b[4:], f.k)
+	binary.BigEndian.PutUint64(b[8:], f.m)
+	copy(b[headerSize:], f.bits)
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < headerSize || string(data[:4]) != string(magic[:]) {
+		return errors.New("bloom: invalid encoding")
+	}
+
+	k := binary.BigEndian.Uint32(data[4:])
+	m := binary.BigEndian.Uint64(data[8:])
+
+	if k == 0 || k > maxK || m == 0 || m%8 != 0 || m/8 != uint64(len(data)-headerSize) {
+		return errors.New("bloom: invalid encoding")
+	}
+
+	f.m, f.k = m, k
+	f.bits = append([]byte(nil), data[headerSize:]...)
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bloom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"testing"
+)
+
+func key(i int) []byte {
+	return []byte(fmt.Sprintf("key-%d", i))
+}
+
+func TestAddTest(t *testing.T) {
+	f := NewWithEstimates(1000, 0.01)
+
+	for i := 0; i < 1000; i++ {
+		f.Add(key(i))
+	}
+
+	for i := 0; i < 1000; i++ {
+		if !f.Test(key(i)) {
+			t.Fatalf("false negative for %q", key(i))
+		}
+	}
+
+	var fp int
+	for i := 1000; i < 11000; i++ {
+		if f.Test(key(i)) {
+			fp++
+		}
+	}
+
+	if rate := float64(fp) / 10000; rate > 0.02 {
+		t.Errorf("false positive rate %f too high", rate)
+	}
+}
+
+func TestUnionIntersect(t *testing.T) {
+	a, b := New(1<<16, 4), New(1<<16, 4)
+
+	for i := 0; i < 200; i++ {
+		a.Add(key(i))
+	}
+
+	for i := 100; i < 300; i++ {
+		b.Add(key(i))
+	}
+
+	u := New(1<<16, 4)
+	if err := u.Union(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := u.Union(b); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 300; i++ {
+		if !u.Test(key(i)) {
+			t.Errorf("union: false negative for %q", key(i))
+		}
+	}
+
+	if err := a.Intersect(b); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 100; i < 200; i++ {
+		if !a.Test(key(i)) {
+			t.Errorf("intersect: false negative for %q", key(i))
+		}
+	}
+
+	var fp int
+	for i := 0; i < 100; i++ {
+		if a.Test(key(i)) {
+			fp++
+		}
+	}
+
+	if fp > 5 {
+		t.Errorf("intersect: %d of 100 removed keys still present", fp)
+	}
+
+	if err := a.Union(New(1<<16, 3)); err != ErrIncompatible {
+		t.Errorf("expected ErrIncompatible, got %v", err)
+	}
+
+	if err := a.Intersect(New(1<<15, 4)); err != ErrIncompatible {
+		t.Errorf("expected ErrIncompatible, got %v", err)
+	}
+}
+
+func TestEstimatedCount(t *testing.T) {
+	f := NewWithEstimates(10000, 0.01)
+
+	for _, n := range []int{10, 100, 1000, 10000} {
+		f.Reset()
+
+		for i := 0; i < n; i++ {
+			f.Add(key(i))
+		}
+
+		est := float64(f.EstimatedCount())
+		if est < 0.95*float64(n) || est > 1.05*float64(n) {
+			t.Errorf("n = %d: estimate %f too far from actual", n, est)
+		}
+	}
+}
+
+// withK returns a copy of the encoded filter b with k replaced.
+func withK(b []byte, k uint32) []byte {
+	b = append([]byte(nil), b...)
+	binary.BigEndian.PutUint32(b[4:], k)
+	return b
+}
+
+func TestBinaryEncoding(t *testing.T) {
+	f := New(1000, 3)
+	for i := 0; i < 50; i++ {
+		f.Add(key(i))
+	}
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var g Filter
+	if err := g.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Cap() != f.Cap() || g.K() != f.K() || !bytes.Equal(g.bits, f.bits) {
+		t.Error("filter changed after round trip")
+	}
+
+	for _, bad := range [][]byte{
+		nil,
+		b[:headerSize-1],
+		b[:len(b)-1],
+		append([]byte{'x'}, b[1:]...),
+		withK(b, 0),
+		withK(b, maxK+1),
+		withK(b, math.MaxUint32),
+	} {
+		if err := g.UnmarshalBinary(bad); err == nil {
+			t.Errorf("UnmarshalBinary succeeded for invalid encoding %x", bad)
+		}
+	}
+}
+
+// TestStableEncoding guards against changes to the hashing or encoding
+// that would make filters incompatible with those already persisted.
+func TestStableEncoding(t *testing.T) {
+	f := New(128, 3)
+	f.Add([]byte("hello"))
+	f.Add([]byte("world"))
+
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const expect = "626c6d0100000003000000000000008001280004000200000000000000000800"
+	if got := hex.EncodeToString(b); got != expect {
+		t.Errorf("expected %s, got %s", expect, got)
+	}
+}
+
+func BenchmarkAdd(b *testing.B) {
+	f := NewWithEstimates(uint64(b.N)+1, 0.01)
+	k := key(0)
+
+	for i := 0; i < b.N; i++ {
+		k[len(k)-1] = byte(i)
+		f.Add(k)
+	}
+}
+
+func BenchmarkUnion(b *testing.B) {
+	f, g := New(1<<27, 4), New(1<<27, 4)
+
+	b.SetBytes(int64(len(f.bits)))
+
+	for i := 0; i < b.N; i++ {
+		f.Union(g)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import "crypto/cipher"
+
+// ctrBatchSize is the number of keystream bytes generated by each
+// refill of a CTR stream. It is rounded down to a multiple of the
+// block size.
+const ctrBatchSize = 4096
+
+// XORKeyStream sets each element in according to dst[i] = src[i] XOR keyStream[i]
+func XORKeyStream(dst, src, keyStream []byte) int {
+	return XOR(dst, src, keyStream)
+}
+
+type ctr struct {
+	b       cipher.Block
+	ctr     []byte
+	out     []byte
+	outUsed int
+}
+
+// NewCTR returns a cipher.Stream which encrypts/decrypts using the given
+// cipher.Block in counter mode. The length of iv must be the same as the
+// Block's block size.
+//
+// Unlike crypto/cipher.NewCTR, the keystream is generated in large batches
+// and applied with XORKeyStream. This only helps ciphers without their own
+// CTR implementation, crypto/cipher.NewCTR is faster for crypto/aes.
+func NewCTR(block cipher.Block, iv []byte) cipher.Stream {
+	bs := block.BlockSize()
+	if len(iv) != bs {
+		panic("bitwise: IV length must equal block size")
+	}
+
+	size := ctrBatchSize - ctrBatchSize%bs
+	if size < bs {
+		size = bs
+	}
+
+	return &ctr{
+		b:   block,
+		ctr: append([]byte(nil), iv...),
+		out: make([]byte, 0, size),
+	}
+}
+
+func (x *ctr) refill() {
+	x.out = x.out[:cap(x.out)]
+
+	bs := x.b.BlockSize()
+	for i := 0; i < len(x.out); i += bs {
+		x.b.Encrypt(x.out[i:], x.ctr)
+
+		for j := len(x.ctr) - 1; j >= 0; j-- {
+			x.ctr[j]++
+			if x.ctr[j] != 0 {
+				break
+			}
+		}
+	}
+
+	x.outUsed = 0
+}
+
+func (x *ctr) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("bitwise: output smaller than input")
+	}
+
+	for len(src) > 0 {
+		if x.outUsed == len(x.out) {
+			x.refill()
+		}
+
+		n := XORKeyStream(dst, src, x.out[x.outUsed:])
+		dst, src = dst[n:], src[n:]
+		x.outUsed += n
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestXORKeyStream(t *testing.T) {
+	testThree(t, XORKeyStream, testXORBytes, xorTestVectors)
+}
+
+func testCTR(t *testing.T, block cipher.Block) {
+	iv := make([]byte, block.BlockSize())
+	for i := range iv {
+		iv[i] = 0xff
+	}
+
+	if err := quick.CheckEqual(func(src []byte, chunks []uint8) []byte {
+		dst := make([]byte, len(src))
+		cipher.NewCTR(block, iv).XORKeyStream(dst, src)
+		return dst
+	}, func(src []byte, chunks []uint8) []byte {
+		dst := make([]byte, len(src))
+		s := NewCTR(block, iv)
+
+		d, p := dst, src
+		for _, c := range chunks {
+			n := int(c) % 64 * 97
+			if n > len(p) {
+				break
+			}
+
+			s.XORKeyStream(d[:n], p[:n])
+			d, p = d[n:], p[n:]
+		}
+
+		s.XORKeyStream(d, p)
+		return dst
+	}, &quick.Config{
+		MaxCountScale: 100,
+	}); err != nil {
+		t.Error(err)
+	}
+
+	src := make([]byte, 3*ctrBatchSize+7)
+	rand.Read(src)
+
+	d1 := make([]byte, len(src))
+	cipher.NewCTR(block, iv).XORKeyStream(d1, src)
+
+	d2 := make([]byte, len(src))
+	NewCTR(block, iv).XORKeyStream(d2, src)
+
+	if !bytes.Equal(d1, d2) {
+		t.Error("not equal")
+	}
+}
+
+func TestCTRAES(t *testing.T) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCTR(t, block)
+}
+
+func TestCTRDES(t *testing.T) {
+	block, err := des.NewCipher(make([]byte, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCTR(t, block)
+}
+
+func benchmarkCTR(b *testing.B, newCTR func(block cipher.Block, iv []byte) cipher.Stream) {
+	block, err := aes.NewCipher(make([]byte, 16))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	iv := make([]byte, block.BlockSize())
+
+	for _, size := range benchSizes {
+		if size.l > 1024*1024 {
+			break
+		}
+
+		b.Run(size.name, func(b *testing.B) {
+			b.SetBytes(int64(size.l))
+
+			buf := make([]byte, size.l)
+			s := newCTR(block, iv)
+
+			for i := 0; i < b.N; i++ {
+				s.XORKeyStream(buf, buf)
+			}
+		})
+	}
+}
+
+func BenchmarkCTR(b *testing.B) {
+	benchmarkCTR(b, NewCTR)
+}
+
+func BenchmarkCTRStdlib(b *testing.B) {
+	benchmarkCTR(b, cipher.NewCTR)
+}
+
+// genericBlock hides any optimised CTR implementation of the
+// underlying cipher.Block from crypto/cipher.
+type genericBlock struct {
+	cipher.Block
+}
+
+// BenchmarkCTRStdlibGeneric measures crypto/cipher's generic CTR mode,
+// which is used for ciphers without an optimised implementation.
+func BenchmarkCTRStdlibGeneric(b *testing.B) {
+	benchmarkCTR(b, func(block cipher.Block, iv []byte) cipher.Stream {
+		return cipher.NewCTR(genericBlock{block}, iv)
+	})
+}
@@ -0,0 +1,253 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"unsafe"
+)
+
+// ClmulLanes sets each 16 bytes of dst to the carry-less product of the
+// corresponding 8 byte lanes of a and b. The lanes and products are
+// little-endian. It returns the number of lanes multiplied, the
+// smallest of len(a)/8, len(b)/8 and len(dst)/16.
+func ClmulLanes(dst, a, b []byte) int {
+	n := len(a) / 8
+	if len(b)/8 < n {
+		n = len(b) / 8
+	}
+	if len(dst)/16 < n {
+		n = len(dst) / 16
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	clmulLanes(dst[:16*n], a[:8*n], b[:8*n])
+	return n
+}
+
+// clmul64 returns the carry-less product of a and b. It processes b a
+// nibble at a time, from the most significant, using a table of the
+// products of a and every nibble.
+func clmul64(a, b uint64) (hi, lo uint64) {
+	var tlo, thi [16]uint64
+	for k := 1; k < 16; k++ {
+		if k%2 == 0 {
+			tlo[k] = tlo[k/2] << 1
+			thi[k] = thi[k/2]<<1 | tlo[k/2]>>63
+		} else {
+			tlo[k] = tlo[k-1] ^ a
+			thi[k] = thi[k-1]
+		}
+	}
+
+	for i := 60; i >= 0; i -= 4 {
+		hi = hi<<4 | lo>>60
+		lo <<= 4
+
+		k := b >> uint(i) & 15
+		lo ^= tlo[k]
+		hi ^= thi[k]
+	}
+
+	return hi, lo
+}
+
+func clmulLanesGeneric(dst, a, b []byte) {
+	for i := 0; i < len(a)/8; i++ {
+		hi, lo := clmul64(binary.LittleEndian.Uint64(a[8*i:]), binary.LittleEndian.Uint64(b[8*i:]))
+		binary.LittleEndian.PutUint64(dst[16*i:], lo)
+		binary.LittleEndian.PutUint64(dst[16*i+8:], hi)
+	}
+}
+
+func clmulAddGeneric(dst, src []uint64, x uint64) {
+	for i, y := range src {
+		hi, lo := clmul64(x, y)
+		dst[i] ^= lo
+		dst[i+1] ^= hi
+	}
+}
+
+// Poly is a polynomial over GF(2). Bit i%64 of p[i/64] is the
+// coefficient of x^i. Trailing zero words are permitted, the
+// polynomials returned by the methods of Poly have none.
+type Poly []uint64
+
+// norm returns p without any trailing zero words.
+func (p Poly) norm() Poly {
+	for len(p) > 0 && p[len(p)-1] == 0 {
+		p = p[:len(p)-1]
+	}
+
+	return p
+}
+
+// Degree returns the degree of p, or -1 if p is zero.
+func (p Poly) Degree() int {
+	p = p.norm()
+	if len(p) == 0 {
+		return -1
+	}
+
+	return 64*len(p) - 1 - bits.LeadingZeros64(p[len(p)-1])
+}
+
+// Equal reports whether p and q are the same polynomial.
+func (p Poly) Equal(q Poly) bool {
+	p, q = p.norm(), q.norm()
+	if len(p) != len(q) {
+		return false
+	}
+
+	for i := range p {
+		if p[i] != q[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// XOR returns the sum of p and q, which is their XOR.
+func (p Poly) XOR(q Poly) Poly {
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+
+	r := append(Poly(nil), p...)
+	if len(q) > 0 {
+		XOR(polyBytes(r), polyBytes(r[:len(q)]), polyBytes(q))
+	}
+
+	return r.norm()
+}
+
+// Mul returns the product of p and q.
+func (p Poly) Mul(q Poly) Poly {
+	p, q = p.norm(), q.norm()
+	if len(p) == 0 || len(q) == 0 {
+		return nil
+	}
+
+	if len(p) < len(q) {
+		p, q = q, p
+	}
+
+	r := make(Poly, len(p)+len(q))
+	for i, x := range q {
+		if x != 0 {
+			clmulAdd(r[i:i+len(p)+1], p, x)
+		}
+	}
+
+	return r.norm()
+}
+
+// Mod returns the remainder of p divided by m. It panics if m is zero.
+//
+// It uses Barrett reduction to clear up to 64 coefficients of p with each
+// carry-less multiply by m.
+func (p Poly) Mod(m Poly) Poly {
+	dm := m.Degree()
+	if dm < 0 {
+		panic("bitwise: division by zero polynomial")
+	}
+
+	m = m.norm()
+	r := append(Poly(nil), p.norm()...)
+
+	// mu is the quotient of x^(dm+64) divided by m, without its x^64
+	// term, which is always set.
+	mu := barrettQuotient(m.bitsAt(dm - 64))
+
+	var qw [2]uint64
+	qm := make(Poly, len(m)+1)
+
+	for d := r.Degree(); d >= dm; d = r.Degree() {
+		s := d - dm - 63
+		if s < 0 {
+			s = 0
+		}
+
+		// t holds the coefficients of r from x^(dm+s) up, which span
+		// fewer than 64 terms, and q = floor(t * x^dm / m) is exactly
+		// the top word of t * (x^64 + mu).
+		t := r.bitsAt(dm + s)
+
+		qw[0], qw[1] = 0, 0
+		clmulAdd(qw[:], []uint64{mu}, t)
+		q := t ^ qw[1]
+
+		for i := range qm {
+			qm[i] = 0
+		}
+
+		clmulAdd(qm, m, q)
+		r.xorShifted(qm.norm(), uint(s))
+		r = r.norm()
+	}
+
+	return r
+}
+
+// barrettQuotient returns the quotient of x^128 divided by x^64 + mh,
+// without its x^64 term.
+func barrettQuotient(mh uint64) (q uint64) {
+	// Subtracting x^64 * (x^64 + mh) from x^128 leaves x^64 * mh. Only
+	// its top word decides the quotient, so the bottom word is dropped.
+	hi := mh
+
+	for i := uint(63); i < 64; i-- {
+		if hi>>i&1 == 0 {
+			continue
+		}
+
+		q |= 1 << i
+		hi ^= 1<<i | mh>>(64-i)
+	}
+
+	return q
+}
+
+// bitsAt returns the 64 coefficients of p from x^pos up. The coefficients
+// of negative powers are zero.
+func (p Poly) bitsAt(pos int) uint64 {
+	if pos < 0 {
+		return p.bitsAt(0) << uint(-pos)
+	}
+
+	w, b := pos/64, uint(pos%64)
+	if w >= len(p) {
+		return 0
+	}
+
+	x := p[w] >> b
+	if b != 0 && w+1 < len(p) {
+		x |= p[w+1] << (64 - b)
+	}
+
+	return x
+}
+
+// xorShifted sets p to p XOR (m * x^s). p must have room for the result.
+func (p Poly) xorShifted(m Poly, s uint) {
+	w, b := s/64, s%64
+
+	for i, x := range m {
+		p[uint(i)+w] ^= x << b
+		if b != 0 && uint(i)+w+1 < uint(len(p)) {
+			p[uint(i)+w+1] ^= x >> (64 - b)
+		}
+	}
+}
+
+func polyBytes(p Poly) []byte {
+	return pointerBytes(unsafe.Pointer(&p[0]), 8*len(p))
+}
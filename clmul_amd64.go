@@ -0,0 +1,40 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+// ClmulAccelerated reports whether ClmulLanes, Poly.Mul and Poly.Mod use
+// a hardware carry-less multiply instruction.
+func ClmulAccelerated() bool {
+	return impl >= implSSE2 && hasPCLMULQDQ
+}
+
+func clmulLanes(dst, a, b []byte) {
+	if ClmulAccelerated() {
+		clmulLanesASM(&dst[0], &a[0], &b[0], uint64(len(a)/8))
+	} else {
+		clmulLanesGeneric(dst, a, b)
+	}
+}
+
+// clmulAdd XORs the carry-less product of x and src into dst, which must
+// have len(src)+1 elements.
+func clmulAdd(dst, src []uint64, x uint64) {
+	if ClmulAccelerated() {
+		clmulAddASM(&dst[0], &src[0], uint64(len(src)), x)
+	} else {
+		clmulAddGeneric(dst, src, x)
+	}
+}
+
+// This function is implemented in bitwise_clmul_amd64.s
+//go:noescape
+func clmulLanesASM(dst, a, b *byte, n uint64)
+
+// This function is implemented in bitwise_clmul_amd64.s
+//go:noescape
+func clmulAddASM(dst, src *uint64, n, x uint64)
@@ -0,0 +1,24 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+// ClmulAccelerated reports whether ClmulLanes, Poly.Mul and Poly.Mod use
+// a hardware carry-less multiply instruction.
+func ClmulAccelerated() bool {
+	return false
+}
+
+func clmulLanes(dst, a, b []byte) {
+	clmulLanesGeneric(dst, a, b)
+}
+
+// clmulAdd XORs the carry-less product of x and src into dst, which must
+// have len(src)+1 elements.
+func clmulAdd(dst, src []uint64, x uint64) {
+	clmulAddGeneric(dst, src, x)
+}
@@ -0,0 +1,249 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+)
+
+// testBits returns the coefficients of p, one per bool.
+func testBits(p Poly) []bool {
+	b := make([]bool, 64*len(p))
+	for i := range b {
+		b[i] = p[i/64]>>uint(i%64)&1 != 0
+	}
+
+	return b
+}
+
+func testFromBits(b []bool) Poly {
+	p := make(Poly, (len(b)+63)/64)
+	for i, bit := range b {
+		if bit {
+			p[i/64] |= 1 << uint(i%64)
+		}
+	}
+
+	return p
+}
+
+func testMulPoly(p, q Poly) Poly {
+	a, b := testBits(p), testBits(q)
+
+	r := make([]bool, len(a)+len(b))
+	for i := range a {
+		for j := range b {
+			if a[i] && b[j] {
+				r[i+j] = !r[i+j]
+			}
+		}
+	}
+
+	return testFromBits(r)
+}
+
+func testModPoly(p, m Poly) Poly {
+	r, b := testBits(p), testBits(m)
+
+	dm := len(b) - 1
+	for dm >= 0 && !b[dm] {
+		dm--
+	}
+
+	for d := len(r) - 1; d >= dm; d-- {
+		if !r[d] {
+			continue
+		}
+
+		for j := 0; j <= dm; j++ {
+			if b[j] {
+				r[d-dm+j] = !r[d-dm+j]
+			}
+		}
+	}
+
+	return testFromBits(r)
+}
+
+func testRandPoly(words int) Poly {
+	p := make(Poly, words)
+	for i := range p {
+		p[i] = rand.Uint64()
+	}
+
+	if words > 0 && rand.Intn(2) == 0 {
+		p[words-1] >>= uint(rand.Intn(64))
+	}
+
+	return p
+}
+
+func TestClmulLanes(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for lanes := 0; lanes <= 20; lanes++ {
+			a := make([]byte, 8*lanes+3)
+			rand.Read(a)
+
+			b := make([]byte, 8*lanes)
+			rand.Read(b)
+
+			dst := make([]byte, 16*lanes+15)
+			if n := ClmulLanes(dst, a, b); n != lanes {
+				t.Errorf("expected %d lanes, got %d", lanes, n)
+			}
+
+			for i := 0; i < lanes; i++ {
+				want := testMulPoly(Poly{binary.LittleEndian.Uint64(a[8*i:])},
+					Poly{binary.LittleEndian.Uint64(b[8*i:])})
+				got := Poly{binary.LittleEndian.Uint64(dst[16*i:]), binary.LittleEndian.Uint64(dst[16*i+8:])}
+
+				if !got.Equal(want) {
+					t.Errorf("lane %d of %d: expected %x, got %x", i, lanes, want, got)
+				}
+			}
+		}
+	})
+}
+
+func TestPolyMul(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			p, q := testRandPoly(rand.Intn(6)), testRandPoly(rand.Intn(6))
+
+			if got, want := p.Mul(q), testMulPoly(p, q); !got.Equal(want) {
+				t.Errorf("%x * %x: expected %x, got %x", p, q, want, got)
+			}
+		}
+	})
+}
+
+func TestPolyMod(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for i := 0; i < 200; i++ {
+			p, m := testRandPoly(rand.Intn(6)), testRandPoly(1+rand.Intn(3))
+			if m.Degree() < 0 {
+				m[0] = 1
+			}
+
+			got, want := p.Mod(m), testModPoly(p, m)
+			if !got.Equal(want) {
+				t.Errorf("%x mod %x: expected %x, got %x", p, m, want, got)
+			}
+
+			if got.Degree() >= m.Degree() {
+				t.Errorf("%x mod %x: remainder %x has degree %d", p, m, got, got.Degree())
+			}
+		}
+	})
+}
+
+func TestPolyModLarge(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for i := 0; i < 50; i++ {
+			p, m := testRandPoly(10+rand.Intn(30)), testRandPoly(1+rand.Intn(8))
+			m[len(m)-1] |= 1 << uint(rand.Intn(64))
+
+			got, want := p.Mod(m), testModPoly(p, m)
+			if !got.Equal(want) {
+				t.Errorf("%x mod %x: expected %x, got %x", p, m, want, got)
+			}
+		}
+
+		// A high power of x modulo a low degree polynomial removes
+		// one coefficient per step.
+		p := make(Poly, 32)
+		p[31] = 1 << 63
+
+		if got, want := p.Mod(Poly{0x11b}), testModPoly(p, Poly{0x11b}); !got.Equal(want) {
+			t.Errorf("x^2047 mod 11b: expected %x, got %x", want, got)
+		}
+	})
+}
+
+func TestPolyModAES(t *testing.T) {
+	// x^8 is x^4 + x^3 + x + 1 modulo the AES polynomial.
+	if got := (Poly{1 << 8}).Mod(Poly{0x11b}); !got.Equal(Poly{0x1b}) {
+		t.Errorf("expected 1b, got %x", got)
+	}
+}
+
+func TestPolyXOR(t *testing.T) {
+	p, q := Poly{1, 2, 3}, Poly{1, 2}
+
+	if got := p.XOR(q); !got.Equal(Poly{0, 0, 3}) {
+		t.Errorf("expected [0 0 3], got %x", got)
+	}
+
+	if got := q.XOR(p); !got.Equal(Poly{0, 0, 3}) {
+		t.Errorf("expected [0 0 3], got %x", got)
+	}
+
+	if got := p.XOR(p); len(got) != 0 || got.Degree() != -1 {
+		t.Errorf("expected zero, got %x", got)
+	}
+
+	if p[0] != 1 || q[0] != 1 {
+		t.Error("XOR modified its operands")
+	}
+}
+
+func TestPolyDegree(t *testing.T) {
+	for _, tc := range []struct {
+		p Poly
+		d int
+	}{
+		{nil, -1},
+		{Poly{0, 0}, -1},
+		{Poly{1}, 0},
+		{Poly{0x11b}, 8},
+		{Poly{0, 1, 0}, 64},
+	} {
+		if d := tc.p.Degree(); d != tc.d {
+			t.Errorf("degree of %x: expected %d, got %d", tc.p, tc.d, d)
+		}
+	}
+}
+
+func TestPolyModZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic")
+		}
+	}()
+
+	Poly{1}.Mod(Poly{0})
+}
+
+func BenchmarkClmulLanes(b *testing.B) {
+	a := make([]byte, 16*1024)
+	rand.Read(a)
+
+	dst := make([]byte, 2*len(a))
+	b.SetBytes(int64(len(a)))
+
+	for i := 0; i < b.N; i++ {
+		ClmulLanes(dst, a, a)
+	}
+}
+
+func BenchmarkPolyMul(b *testing.B) {
+	p, q := testRandPoly(64), testRandPoly(64)
+
+	for i := 0; i < b.N; i++ {
+		p.Mul(q)
+	}
+}
+
+func BenchmarkPolyMod(b *testing.B) {
+	p, m := testRandPoly(128), testRandPoly(2)
+	m[1] |= 1
+
+	for i := 0; i < b.N; i++ {
+		p.Mod(m)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+// Compress copies each byte src[i] for which bit i%8 of mask[i/8] is set
+// to consecutive bytes of dst. The bits are in LSBFirst order, as
+// produced by EqualMask and the other mask operations. Bytes of src
+// beyond 8*len(mask) are ignored. It stops once dst is full and returns
+// the number of bytes written to dst. dst may be the same slice as src,
+// but must not otherwise overlap it.
+func Compress(dst, src, mask []byte) int {
+	// dst may be src as the bytes are only ever moved towards the start.
+	if inexactOverlap(dst, src[:maskLen(mask, src)]) {
+		panic("bitwise: invalid buffer overlap")
+	}
+
+	return compress(dst, src, mask)
+}
+
+// Expand is the inverse of Compress. It copies consecutive bytes of src
+// to each byte dst[i] for which bit i%8 of mask[i/8] is set and sets
+// the other bytes of dst to zero. Bytes of dst beyond 8*len(mask) are
+// not modified. It stops before the first selected byte of dst once src
+// is exhausted and returns the number of bytes read from src. dst must
+// not overlap src at all.
+func Expand(dst, src, mask []byte) int {
+	// The bytes are moved towards the end, so even an exact overlap
+	// would overwrite bytes of src before they are read.
+	if Overlaps(dst[:maskLen(mask, dst)], src) {
+		panic("bitwise: invalid buffer overlap")
+	}
+
+	return expand(dst, src, mask)
+}
+
+func compressGeneric(dst, src, mask []byte) int {
+	var k int
+	for i, c := range src {
+		if mask[i/8]&(1<<uint(i%8)) == 0 {
+			continue
+		}
+
+		if k == len(dst) {
+			break
+		}
+
+		dst[k] = c
+		k++
+	}
+
+	return k
+}
+
+func expandGeneric(dst, src, mask []byte) int {
+	var k int
+	for i := range dst {
+		if mask[i/8]&(1<<uint(i%8)) == 0 {
+			dst[i] = 0
+			continue
+		}
+
+		if k == len(src) {
+			break
+		}
+
+		dst[i] = src[k]
+		k++
+	}
+
+	return k
+}
@@ -0,0 +1,74 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+func compress(dst, src, mask []byte) int {
+	n := maskLen(mask, src)
+
+	// k bytes of dst have been written from the first i bytes of src.
+	var i, k int
+	switch {
+	case len(dst) == 0:
+	case impl >= implAVX512 && hasAVX512VBMI2 && n >= 64:
+		w, m := compressAVX512(&dst[0], &src[0], &mask[0], uint64(len(dst)), uint64(n/64))
+		i, k = 64*int(m), int(w)
+	case impl >= implAVX2 && n >= 16:
+		w, m := compressAVX2(&dst[0], &src[0], &mask[0], uint64(len(dst)), uint64(n/16))
+		i, k = 16*int(m), int(w)
+	case impl >= implSSE2 && hasSSSE3 && n >= 8:
+		w, m := compressASM(&dst[0], &src[0], &mask[0], uint64(len(dst)), uint64(n/8))
+		i, k = 8*int(m), int(w)
+	}
+
+	return k + compressGeneric(dst[k:], src[i:n], mask[i/8:])
+}
+
+func expand(dst, src, mask []byte) int {
+	n := maskLen(mask, dst)
+
+	// k bytes of src have been read into the first i bytes of dst.
+	var i, k int
+	switch {
+	case len(src) == 0:
+	case impl >= implAVX512 && hasAVX512VBMI2 && n >= 64:
+		r, m := expandAVX512(&dst[0], &src[0], &mask[0], uint64(len(src)), uint64(n/64))
+		i, k = 64*int(m), int(r)
+	case impl >= implAVX2 && n >= 16:
+		r, m := expandAVX2(&dst[0], &src[0], &mask[0], uint64(len(src)), uint64(n/16))
+		i, k = 16*int(m), int(r)
+	case impl >= implSSE2 && hasSSSE3 && n >= 8:
+		r, m := expandASM(&dst[0], &src[0], &mask[0], uint64(len(src)), uint64(n/8))
+		i, k = 8*int(m), int(r)
+	}
+
+	return k + expandGeneric(dst[i:n], src[k:], mask[i/8:])
+}
+
+// This function is implemented in bitwise_compress_amd64.s
+//go:noescape
+func compressASM(dst, src, mask *byte, limit, blocks uint64) (n, processed uint64)
+
+// This function is implemented in bitwise_compress_amd64.s
+//go:noescape
+func expandASM(dst, src, mask *byte, limit, blocks uint64) (n, processed uint64)
+
+// This function is implemented in bitwise_compress_amd64.s
+//go:noescape
+func compressAVX2(dst, src, mask *byte, limit, blocks uint64) (n, processed uint64)
+
+// This function is implemented in bitwise_compress_amd64.s
+//go:noescape
+func expandAVX2(dst, src, mask *byte, limit, blocks uint64) (n, processed uint64)
+
+// This function is implemented in bitwise_compress_amd64.s
+//go:noescape
+func compressAVX512(dst, src, mask *byte, limit, blocks uint64) (n, processed uint64)
+
+// This function is implemented in bitwise_compress_amd64.s
+//go:noescape
+func expandAVX512(dst, src, mask *byte, limit, blocks uint64) (n, processed uint64)
@@ -0,0 +1,16 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+func compress(dst, src, mask []byte) int {
+	return compressGeneric(dst, src[:maskLen(mask, src)], mask)
+}
+
+func expand(dst, src, mask []byte) int {
+	return expandGeneric(dst[:maskLen(mask, dst)], src, mask)
+}
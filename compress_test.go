@@ -0,0 +1,196 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func testCompressBytes(dst, src, mask []byte) int {
+	var k int
+	for i := 0; i < len(src) && i < 8*len(mask) && k < len(dst); i++ {
+		if mask[i/8]>>uint(i%8)&1 != 0 {
+			dst[k] = src[i]
+			k++
+		}
+	}
+
+	return k
+}
+
+func testExpandBytes(dst, src, mask []byte) int {
+	var k int
+	for i := 0; i < len(dst) && i < 8*len(mask); i++ {
+		if mask[i/8]>>uint(i%8)&1 == 0 {
+			dst[i] = 0
+			continue
+		}
+
+		if k == len(src) {
+			break
+		}
+
+		dst[i] = src[k]
+		k++
+	}
+
+	return k
+}
+
+// testMasks returns masks of n bits with different densities.
+func testMasks(n int) [][]byte {
+	var masks [][]byte
+	for _, density := range []int{0, 1, 50, 99, 100} {
+		mask := make([]byte, (n+7)/8)
+		for i := 0; i < n; i++ {
+			if rand.Intn(100) < density {
+				mask[i/8] |= 1 << uint(i%8)
+			}
+		}
+
+		masks = append(masks, mask)
+	}
+
+	return masks
+}
+
+func TestCompress(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for _, size := range []int{0, 1, 7, 8, 9, 15, 16, 17, 33, 63, 64, 65, 200, 1000} {
+			src := make([]byte, size)
+			rand.Read(src)
+
+			for _, mask := range testMasks(size) {
+				for _, dstLen := range []int{0, 5, size / 3, size, size + 10} {
+					d1 := make([]byte, dstLen)
+					n1 := Compress(d1, src, mask)
+
+					d2 := make([]byte, dstLen)
+					n2 := testCompressBytes(d2, src, mask)
+
+					if n1 != n2 {
+						t.Errorf("size %d with %d byte dst: expected %d bytes, got %d", size, dstLen, n2, n1)
+					}
+
+					if !bytes.Equal(d1[:n1], d2[:n2]) {
+						t.Errorf("size %d with %d byte dst: expected %x, got %x", size, dstLen, d2[:n2], d1[:n1])
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestExpand(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for _, size := range []int{0, 1, 7, 8, 9, 15, 16, 17, 33, 63, 64, 65, 200, 1000} {
+			for _, mask := range testMasks(size) {
+				for _, srcLen := range []int{0, 5, size / 3, size, size + 10} {
+					src := make([]byte, srcLen)
+					rand.Read(src)
+
+					d1 := bytes.Repeat([]byte{0xaa}, size)
+					n1 := Expand(d1, src, mask)
+
+					d2 := bytes.Repeat([]byte{0xaa}, size)
+					n2 := testExpandBytes(d2, src, mask)
+
+					if n1 != n2 {
+						t.Errorf("size %d with %d byte src: expected %d bytes, got %d", size, srcLen, n2, n1)
+					}
+
+					if !bytes.Equal(d1, d2) {
+						t.Errorf("size %d with %d byte src: expected %x, got %x", size, srcLen, d2, d1)
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestCompressExpand(t *testing.T) {
+	src := make([]byte, 1000)
+	rand.Read(src)
+
+	mask := testMasks(len(src))[2]
+
+	dense := make([]byte, len(src))
+	n := Compress(dense, src, mask)
+
+	dst := make([]byte, len(src))
+	if m := Expand(dst, dense[:n], mask); m != n {
+		t.Fatalf("expected Expand to read %d bytes, read %d", n, m)
+	}
+
+	And(src, src, dst)
+
+	if !bytes.Equal(dst, src) {
+		t.Error("Expand did not reverse Compress")
+	}
+}
+
+func benchmarkCompress(b *testing.B, fn func(dst, src, mask []byte) int) {
+	src := make([]byte, 16*1024)
+	rand.Read(src)
+
+	mask := testMasks(len(src))[2]
+	dst := make([]byte, len(src))
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		fn(dst, src, mask)
+	}
+}
+
+func TestCompressExpandOverlap(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for _, size := range []int{16, 100, 1000} {
+			src := make([]byte, size)
+			rand.Read(src)
+
+			mask := testMasks(size)[2]
+
+			want := make([]byte, size)
+			n := testCompressBytes(want, src, mask)
+
+			got := append([]byte(nil), src...)
+			if Compress(got, got, mask) != n || !bytes.Equal(got[:n], want[:n]) {
+				t.Errorf("Compress not equal with dst the same as src for size %d", size)
+			}
+
+			buf := make([]byte, 2*size)
+			if !testPanics(func() {
+				Compress(buf[1:size+1], buf[:size], mask)
+			}) {
+				t.Errorf("Compress: no panic for partial overlap of size %d", size)
+			}
+
+			if !testPanics(func() {
+				Expand(buf[:size], buf[:size], mask)
+			}) {
+				t.Errorf("Expand: no panic for dst the same as src of size %d", size)
+			}
+
+			if !testPanics(func() {
+				Expand(buf[1:size+1], buf[:size], mask)
+			}) {
+				t.Errorf("Expand: no panic for partial overlap of size %d", size)
+			}
+		}
+	})
+}
+
+func BenchmarkCompress(b *testing.B) {
+	benchmarkCompress(b, Compress)
+}
+
+func BenchmarkExpand(b *testing.B) {
+	benchmarkCompress(b, func(dst, src, mask []byte) int {
+		return Expand(src, dst, mask)
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+// The CPU features are set by a variable initialiser, rather than by init,
+// so that impl, which depends on them, is initialised after them.
+var hasSSSE3, hasPCLMULQDQ, hasAVX2, hasAVX512, hasAVX512VBMI2, hasAVX512VPOPCNTDQ = detectCPU()
+
+// detectCPU returns the supported CPU features. avx512 is only set if
+// both AVX-512F and AVX-512BW are supported.
+func detectCPU() (ssse3, pclmulqdq, avx2, avx512, avx512VBMI2, avx512VPOPCNTDQ bool) {
+	maxID, _, _, _ := cpuid(0, 0)
+	if maxID < 1 {
+		return
+	}
+
+	_, _, ecx1, _ := cpuid(1, 0)
+	ssse3 = ecx1&(1<<9) != 0
+	pclmulqdq = ecx1&(1<<1) != 0
+
+	// The OS must save the YMM and ZMM registers on a context
+	// switch for AVX and AVX-512 to be usable.
+	var osYMM, osZMM bool
+	if ecx1&(1<<27) != 0 {
+		eax, _ := xgetbv()
+		osYMM = eax&0x06 == 0x06
+		osZMM = osYMM && eax&0xe0 == 0xe0
+	}
+
+	if maxID < 7 {
+		return
+	}
+
+	_, ebx7, ecx7, _ := cpuid(7, 0)
+
+	hasAVX := ecx1&(1<<28) != 0
+	avx2 = hasAVX && osYMM && ebx7&(1<<5) != 0
+
+	avx512 = osZMM && ebx7&(1<<16) != 0 && ebx7&(1<<30) != 0
+	avx512VBMI2 = avx512 && ecx7&(1<<6) != 0
+	avx512VPOPCNTDQ = avx512 && ecx7&(1<<14) != 0
+	return
+}
+
+// This function is implemented in cpu_amd64.s
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// This function is implemented in cpu_amd64.s
+func xgetbv() (eax, edx uint32)
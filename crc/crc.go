@@ -0,0 +1,310 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// Package crc implements 32 and 64 bit cyclic redundancy checks with
+// arbitrary polynomials.
+//
+// The checksums match those of hash/crc32 and hash/crc64 for the same
+// polynomial. Long inputs are folded with carry-less multiplication,
+// using bitwise.ClmulLanes, when it is accelerated.
+package crc
+
+import (
+	"encoding/binary"
+	"hash"
+
+	"github.com/tmthrgd/go-bitwise"
+)
+
+// Predefined polynomials, in the reversed notation used by hash/crc32
+// and hash/crc64.
+const (
+	// IEEE is the CRC-32 polynomial used by Ethernet, gzip and PNG.
+	IEEE = 0xedb88320
+
+	// Castagnoli is the CRC-32C polynomial used by iSCSI and ext4.
+	Castagnoli = 0x82f63b78
+
+	// Koopman is Koopman's CRC-32 polynomial.
+	Koopman = 0xeb31d82e
+
+	// ISO is the CRC-64 polynomial defined in ISO 3309.
+	ISO = 0xd800000000000000
+
+	// ECMA is the CRC-64 polynomial defined in ECMA 182.
+	ECMA = 0xc96c5795d7870f42
+)
+
+// foldBlock is the number of bytes folded at a time, as eight 16 byte
+// lanes.
+const foldBlock = 128
+
+// foldThreshold is the length at and above which Update folds the
+// input rather than using the tables.
+const foldThreshold = 4 * foldBlock
+
+// Table holds the precomputed state for a polynomial. It is safe for
+// concurrent use.
+type Table struct {
+	width int
+	mask  uint64
+
+	// poly is the polynomial in normal notation, with the x^width
+	// term.
+	poly bitwise.Poly
+
+	// slicing[k][b] is the register after processing the byte b
+	// followed by k zero bytes, starting from zero.
+	slicing [8][256]uint64
+
+	// fold holds the constants that move each 16 byte lane forward
+	// by foldBlock bytes, x^(64+1023) and x^1023 modulo poly for the
+	// first and last 8 bytes of the lane, repeated for each lane.
+	fold [foldBlock]byte
+}
+
+// MakeTable32 returns a Table for the 32 bit polynomial poly, given in
+// reversed notation.
+func MakeTable32(poly uint32) *Table {
+	return makeTable(32, uint64(poly))
+}
+
+// MakeTable64 returns a Table for the 64 bit polynomial poly, given in
+// reversed notation.
+func MakeTable64(poly uint64) *Table {
+	return makeTable(64, poly)
+}
+
+func makeTable(width int, rev uint64) *Table {
+	t := &Table{
+		width: width,
+		mask:  ^uint64(0) >> uint(64-width),
+	}
+
+	t.poly = bitwise.Poly{0, 0}
+	t.poly[width/64] |= 1 << uint(width%64)
+	for j := 0; j < width; j++ {
+		if rev>>uint(j)&1 != 0 {
+			t.poly[(width-1-j)/64] |= 1 << uint((width-1-j)%64)
+		}
+	}
+
+	for b := range t.slicing[0] {
+		r := uint64(b)
+		for i := 0; i < 8; i++ {
+			if r&1 != 0 {
+				r = r>>1 ^ rev
+			} else {
+				r >>= 1
+			}
+		}
+
+		t.slicing[0][b] = r
+	}
+
+	for k := 1; k < len(t.slicing); k++ {
+		for b := range t.slicing[k] {
+			r := t.slicing[k-1][b]
+			t.slicing[k][b] = r>>8 ^ t.slicing[0][r&0xff]
+		}
+	}
+
+	// The carry-less product of two reflected quadwords is reflected
+	// into 127 bits shifted up by one, so each exponent is one less
+	// than the distance it moves the lane.
+	k1 := reflect64(t.xpow(64 + 8*foldBlock - 1))
+	k2 := reflect64(t.xpow(8*foldBlock - 1))
+
+	for i := 0; i < foldBlock; i += 16 {
+		binary.LittleEndian.PutUint64(t.fold[i:], k1)
+		binary.LittleEndian.PutUint64(t.fold[i+8:], k2)
+	}
+
+	return t
+}
+
+// xpow returns x^n modulo the polynomial.
+func (t *Table) xpow(n uint64) bitwise.Poly {
+	return t.pow(bitwise.Poly{2}, n)
+}
+
+// pow returns p^n modulo the polynomial.
+func (t *Table) pow(p bitwise.Poly, n uint64) bitwise.Poly {
+	r := bitwise.Poly{1}
+	sq := p
+
+	for ; n != 0; n >>= 1 {
+		if n&1 != 0 {
+			r = r.Mul(sq).Mod(t.poly)
+		}
+
+		sq = sq.Mul(sq).Mod(t.poly)
+	}
+
+	return r
+}
+
+// reflect64 returns p, which must have degree less than 64, with the
+// coefficient of x^i in bit 63-i.
+func reflect64(p bitwise.Poly) uint64 {
+	var r uint64
+	for i := 0; i <= p.Degree(); i++ {
+		if p[i/64]>>uint(i%64)&1 != 0 {
+			r |= 1 << uint(63-i)
+		}
+	}
+
+	return r
+}
+
+// toPoly returns the register r as a polynomial.
+func (t *Table) toPoly(r uint64) bitwise.Poly {
+	p := bitwise.Poly{0}
+	for j := 0; j < t.width; j++ {
+		if r>>uint(j)&1 != 0 {
+			p[0] |= 1 << uint(t.width-1-j)
+		}
+	}
+
+	return p
+}
+
+// fromPoly is the inverse of toPoly.
+func (t *Table) fromPoly(p bitwise.Poly) uint64 {
+	return reflect64(p) >> uint(64-t.width)
+}
+
+// update returns the register r after processing p, without the
+// initial and final inversions.
+func (t *Table) update(r uint64, p []byte) uint64 {
+	for len(p) >= 8 {
+		r ^= binary.LittleEndian.Uint64(p)
+		r = t.slicing[7][r&0xff] ^
+			t.slicing[6][r>>8&0xff] ^
+			t.slicing[5][r>>16&0xff] ^
+			t.slicing[4][r>>24&0xff] ^
+			t.slicing[3][r>>32&0xff] ^
+			t.slicing[2][r>>40&0xff] ^
+			t.slicing[1][r>>48&0xff] ^
+			t.slicing[0][r>>56]
+		p = p[8:]
+	}
+
+	for _, b := range p {
+		r = t.slicing[0][byte(r)^b] ^ r>>8
+	}
+
+	return r
+}
+
+// updateFold is equivalent to update but folds the input with
+// carry-less multiplication. p must be at least foldBlock bytes long.
+func (t *Table) updateFold(r uint64, p []byte) uint64 {
+	// Eight 16 byte lanes are folded independently. After each
+	// iteration the lanes hold a foldBlock byte message that leaves
+	// the same remainder as the input processed so far.
+	var lanes [2 * foldBlock / 16]uint64
+	for i := range lanes {
+		lanes[i] = binary.LittleEndian.Uint64(p[8*i:])
+	}
+
+	// The register is equivalent to XORing it into the first bytes
+	// of the input.
+	lanes[0] ^= r
+	p = p[foldBlock:]
+
+	var buf [foldBlock]byte
+	var prod [2 * foldBlock]byte
+
+	for ; len(p) >= foldBlock; p = p[foldBlock:] {
+		for i, x := range lanes {
+			binary.LittleEndian.PutUint64(buf[8*i:], x)
+		}
+
+		bitwise.ClmulLanes(prod[:], buf[:], t.fold[:])
+
+		for j := 0; j < len(lanes); j += 2 {
+			q := prod[16*j:]
+			lanes[j] = binary.LittleEndian.Uint64(q) ^
+				binary.LittleEndian.Uint64(q[16:]) ^
+				binary.LittleEndian.Uint64(p[8*j:])
+			lanes[j+1] = binary.LittleEndian.Uint64(q[8:]) ^
+				binary.LittleEndian.Uint64(q[24:]) ^
+				binary.LittleEndian.Uint64(p[8*j+8:])
+		}
+	}
+
+	for i, x := range lanes {
+		binary.LittleEndian.PutUint64(buf[8*i:], x)
+	}
+
+	return t.update(t.update(0, buf[:]), p)
+}
+
+// Update returns the result of adding the bytes in p to the crc.
+func Update(crc uint64, tab *Table, p []byte) uint64 {
+	r := ^crc & tab.mask
+
+	if len(p) >= foldThreshold && bitwise.ClmulAccelerated() {
+		r = tab.updateFold(r, p)
+	} else {
+		r = tab.update(r, p)
+	}
+
+	return ^r & tab.mask
+}
+
+// Checksum returns the CRC of data using the polynomial represented by
+// the Table.
+func Checksum(data []byte, tab *Table) uint64 {
+	return Update(0, tab, data)
+}
+
+// Combine returns the CRC of the concatenation of two inputs given the
+// CRC of each, crc1 and crc2, and the length of the second, len2. It
+// allows the CRC of a long input to be computed in parallel chunks.
+func Combine(tab *Table, crc1, crc2 uint64, len2 int64) uint64 {
+	if len2 <= 0 {
+		return crc1
+	}
+
+	// The inversions cancel, so the first CRC only has to be moved
+	// forward over len2 zero bytes, multiplying it by (x^8)^len2.
+	p := tab.toPoly(crc1).Mul(tab.pow(bitwise.Poly{1 << 8}, uint64(len2))).Mod(tab.poly)
+	return tab.fromPoly(p) ^ crc2
+}
+
+// digest implements hash.Hash64.
+type digest struct {
+	crc uint64
+	tab *Table
+}
+
+// New creates a new hash.Hash64 computing the CRC using the polynomial
+// represented by the Table. Its Sum method lays the value out in
+// big-endian byte order, it is 4 bytes long for 32 bit polynomials and
+// 8 bytes long for 64 bit polynomials.
+func New(tab *Table) hash.Hash64 {
+	return &digest{0, tab}
+}
+
+func (d *digest) Size() int { return d.tab.width / 8 }
+
+func (d *digest) BlockSize() int { return 1 }
+
+func (d *digest) Reset() { d.crc = 0 }
+
+func (d *digest) Write(p []byte) (n int, err error) {
+	d.crc = Update(d.crc, d.tab, p)
+	return len(p), nil
+}
+
+func (d *digest) Sum64() uint64 { return d.crc }
+
+func (d *digest) Sum(in []byte) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], d.crc)
+	return append(in, b[8-d.Size():]...)
+}
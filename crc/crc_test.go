@@ -0,0 +1,202 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package crc
+
+import (
+	"hash/crc32"
+	"hash/crc64"
+	"math/rand"
+	"testing"
+)
+
+var testLengths = []int{0, 1, 7, 8, 9, 100, foldBlock, foldThreshold - 1, foldThreshold,
+	foldThreshold + 1, foldThreshold + 127, 1000, 4096 + 13, 100000}
+
+// testCRC is a bit at a time reference implementation.
+func testCRC(width int, rev uint64, data []byte) uint64 {
+	mask := ^uint64(0) >> uint(64-width)
+
+	r := mask
+	for _, b := range data {
+		r ^= uint64(b)
+		for i := 0; i < 8; i++ {
+			if r&1 != 0 {
+				r = r>>1 ^ rev
+			} else {
+				r >>= 1
+			}
+		}
+	}
+
+	return ^r & mask
+}
+
+func TestCRC32(t *testing.T) {
+	for _, poly := range []uint32{IEEE, Castagnoli, Koopman} {
+		tab, ref := MakeTable32(poly), crc32.MakeTable(poly)
+
+		for _, n := range testLengths {
+			data := make([]byte, n)
+			rand.Read(data)
+
+			if got, want := Checksum(data, tab), uint64(crc32.Checksum(data, ref)); got != want {
+				t.Errorf("poly %08x, length %d: expected %08x, got %08x", poly, n, want, got)
+			}
+		}
+	}
+}
+
+func TestCRC64(t *testing.T) {
+	for _, poly := range []uint64{ISO, ECMA} {
+		tab, ref := MakeTable64(poly), crc64.MakeTable(poly)
+
+		for _, n := range testLengths {
+			data := make([]byte, n)
+			rand.Read(data)
+
+			if got, want := Checksum(data, tab), crc64.Checksum(data, ref); got != want {
+				t.Errorf("poly %016x, length %d: expected %016x, got %016x", poly, n, want, got)
+			}
+		}
+	}
+}
+
+func TestArbitraryPolynomials(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		rev32 := rand.Uint32() | 1<<31
+		rev64 := rand.Uint64() | 1<<63
+
+		tab32, tab64 := MakeTable32(rev32), MakeTable64(rev64)
+
+		for _, n := range []int{0, 13, foldThreshold + 77, 3000} {
+			data := make([]byte, n)
+			rand.Read(data)
+
+			if got, want := Checksum(data, tab32), testCRC(32, uint64(rev32), data); got != want {
+				t.Errorf("poly %08x, length %d: expected %08x, got %08x", rev32, n, want, got)
+			}
+
+			if got, want := Checksum(data, tab64), testCRC(64, rev64, data); got != want {
+				t.Errorf("poly %016x, length %d: expected %016x, got %016x", rev64, n, want, got)
+			}
+		}
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tab := MakeTable64(ECMA)
+
+	data := make([]byte, 5000)
+	rand.Read(data)
+
+	want := Checksum(data, tab)
+
+	var crc uint64
+	for p := data; len(p) > 0; {
+		n := rand.Intn(2 * foldThreshold)
+		if n > len(p) {
+			n = len(p)
+		}
+
+		crc = Update(crc, tab, p[:n])
+		p = p[n:]
+	}
+
+	if crc != want {
+		t.Errorf("expected %016x, got %016x", want, crc)
+	}
+}
+
+func TestCombine(t *testing.T) {
+	for _, tab := range []*Table{MakeTable32(IEEE), MakeTable32(Castagnoli), MakeTable64(ISO)} {
+		data := make([]byte, 3000)
+		rand.Read(data)
+
+		want := Checksum(data, tab)
+
+		for _, split := range []int{0, 1, 100, 1500, 2999, 3000} {
+			crc1 := Checksum(data[:split], tab)
+			crc2 := Checksum(data[split:], tab)
+
+			if got := Combine(tab, crc1, crc2, int64(len(data)-split)); got != want {
+				t.Errorf("split at %d: expected %x, got %x", split, want, got)
+			}
+		}
+	}
+}
+
+func TestHash(t *testing.T) {
+	data := []byte("The quick brown fox jumps over the lazy dog")
+
+	h := New(MakeTable32(IEEE))
+	h.Write(data[:10])
+	h.Write(data[10:])
+
+	if got, want := h.Sum64(), uint64(crc32.ChecksumIEEE(data)); got != want {
+		t.Errorf("expected %08x, got %08x", want, got)
+	}
+
+	ref := crc32.NewIEEE()
+	ref.Write(data)
+
+	if got, want := h.Sum(nil), ref.Sum(nil); string(got) != string(want) {
+		t.Errorf("expected Sum %x, got %x", want, got)
+	}
+
+	h64 := New(MakeTable64(ISO))
+	h64.Write(data)
+
+	ref64 := crc64.New(crc64.MakeTable(crc64.ISO))
+	ref64.Write(data)
+
+	if got, want := h64.Sum(nil), ref64.Sum(nil); string(got) != string(want) {
+		t.Errorf("expected Sum %x, got %x", want, got)
+	}
+
+	h.Reset()
+	if h.Sum64() != 0 {
+		t.Error("Reset did not clear the hash")
+	}
+}
+
+func benchmarkChecksum(b *testing.B, fn func(data []byte) uint64) {
+	data := make([]byte, 64*1024)
+	rand.Read(data)
+
+	b.SetBytes(int64(len(data)))
+
+	for i := 0; i < b.N; i++ {
+		fn(data)
+	}
+}
+
+func BenchmarkCRC64(b *testing.B) {
+	tab := MakeTable64(ECMA)
+	benchmarkChecksum(b, func(data []byte) uint64 {
+		return Checksum(data, tab)
+	})
+}
+
+func BenchmarkCRC64Stdlib(b *testing.B) {
+	tab := crc64.MakeTable(crc64.ECMA)
+	benchmarkChecksum(b, func(data []byte) uint64 {
+		return crc64.Checksum(data, tab)
+	})
+}
+
+func BenchmarkCRC32Koopman(b *testing.B) {
+	tab := MakeTable32(Koopman)
+	benchmarkChecksum(b, func(data []byte) uint64 {
+		return Checksum(data, tab)
+	})
+}
+
+func BenchmarkCRC32KoopmanStdlib(b *testing.B) {
+	tab := crc32.MakeTable(crc32.Koopman)
+	benchmarkChecksum(b, func(data []byte) uint64 {
+		return uint64(crc32.Checksum(data, tab))
+	})
+}
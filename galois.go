@@ -0,0 +1,43 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+// galoisPoly is the low byte of the reduction polynomial
+// x^8 + x^4 + x^3 + x^2 + 1 (0x11d) used for GF(2^8).
+const galoisPoly = 0x1d
+
+// galoisTables fills t with the products of c and every nibble,
+// t[x] = c * x and t[16+x] = c * (x << 4).
+func galoisTables(t *[32]byte, c byte) {
+	var basis [8]byte
+	for i := range basis {
+		basis[i] = c
+
+		hi := c & 0x80
+		c <<= 1
+		if hi != 0 {
+			c ^= galoisPoly
+		}
+	}
+
+	for x := 1; x < 16; x++ {
+		var lo, hi byte
+		for i := uint(0); i < 4; i++ {
+			if x&(1<<i) != 0 {
+				lo ^= basis[i]
+				hi ^= basis[4+i]
+			}
+		}
+
+		t[x], t[16+x] = lo, hi
+	}
+}
+
+func galoisMulXORGeneric(dst, src []byte, t *[32]byte) {
+	for i, s := range src[:len(dst)] {
+		dst[i] ^= t[s&0x0f] ^ t[16+s>>4]
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+// GaloisMulXOR sets each element in according to dst[i] = dst[i] XOR (c * src[i])
+// where the multiplication is in GF(2^8) with the polynomial x^8 + x^4 + x^3 + x^2 + 1.
+func GaloisMulXOR(dst, src []byte, c byte) int {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	checkOverlap(dst[:n], src[:n], src[:n])
+
+	switch c {
+	case 0:
+		return n
+	case 1:
+		return XOR(dst, dst[:n], src)
+	}
+
+	var t [32]byte
+	galoisTables(&t, c)
+
+	galoisMulXORGeneric(dst[:n], src[:n], &t)
+	return n
+}
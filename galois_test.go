@@ -0,0 +1,167 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func testGaloisMul(a, b byte) byte {
+	var p byte
+	for ; b != 0; b >>= 1 {
+		if b&1 != 0 {
+			p ^= a
+		}
+
+		if a&0x80 != 0 {
+			a = a<<1 ^ galoisPoly
+		} else {
+			a <<= 1
+		}
+	}
+
+	return p
+}
+
+func testGaloisMulXOR(dst, src []byte, c byte) int {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	for i := 0; i < n; i++ {
+		dst[i] ^= testGaloisMul(c, src[i])
+	}
+
+	return n
+}
+
+func TestGaloisMul(t *testing.T) {
+	for _, v := range []struct{ a, b, p byte }{
+		{0x02, 0x8e, 0x01},
+		{0x80, 0x02, 0x1d},
+		{0xff, 0x00, 0x00},
+		{0x01, 0xb7, 0xb7},
+		{0x53, 0xca, 0x8f},
+	} {
+		if p := testGaloisMul(v.a, v.b); p != v.p {
+			t.Errorf("%#02x * %#02x: expected %#02x, got %#02x", v.a, v.b, v.p, p)
+		}
+
+		dst := make([]byte, 33)
+		src := bytes.Repeat([]byte{v.b}, len(dst))
+		GaloisMulXOR(dst, src, v.a)
+
+		if !bytes.Equal(dst, bytes.Repeat([]byte{v.p}, len(dst))) {
+			t.Errorf("GaloisMulXOR %#02x * %#02x: expected %#02x, got %x", v.a, v.b, v.p, dst)
+		}
+	}
+}
+
+func TestGaloisMulXOR(t *testing.T) {
+	for c := 0; c < 256; c++ {
+		dst := make([]byte, 1024+15)
+		rand.Read(dst)
+
+		src := make([]byte, len(dst))
+		rand.Read(src)
+
+		d1 := append([]byte(nil), dst...)
+		GaloisMulXOR(d1, src, byte(c))
+
+		d2 := append([]byte(nil), dst...)
+		testGaloisMulXOR(d2, src, byte(c))
+
+		if !bytes.Equal(d1, d2) {
+			t.Errorf("c = %#02x: not equal", c)
+		}
+
+		var tab [32]byte
+		galoisTables(&tab, byte(c))
+
+		d3 := append([]byte(nil), dst...)
+		galoisMulXORGeneric(d3, src, &tab)
+
+		if !bytes.Equal(d3, d2) {
+			t.Errorf("c = %#02x: generic not equal", c)
+		}
+	}
+
+	if err := quick.CheckEqual(func(dst, src []byte, c byte) []byte {
+		d := append([]byte{}, dst...)
+		testGaloisMulXOR(d, src, c)
+		return d
+	}, func(dst, src []byte, c byte) []byte {
+		GaloisMulXOR(dst, src, c)
+		return dst
+	}, &quick.Config{
+		MaxCountScale: 500,
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGaloisMulXOROverlap(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for _, size := range []int{2, 15, 100, 1000} {
+			src := make([]byte, size)
+			rand.Read(src)
+
+			want := append([]byte(nil), src...)
+			testGaloisMulXOR(want, src, 7)
+
+			got := append([]byte(nil), src...)
+			GaloisMulXOR(got, got, 7)
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("not equal with dst the same as src for size %d", size)
+			}
+
+			buf := make([]byte, 2*size)
+			if !testPanics(func() {
+				GaloisMulXOR(buf[1:size+1], buf[:size], 7)
+			}) {
+				t.Errorf("no panic for partial overlap of size %d", size)
+			}
+		}
+	})
+}
+
+func benchmarkGalois(b *testing.B, fn func(dst, src []byte, c byte) int) {
+	maxSize := benchSizes[len(benchSizes)-1]
+
+	dst, src := make([]byte, maxSize.l), make([]byte, maxSize.l)
+	rand.Read(src)
+
+	for _, size := range benchSizes {
+		b.Run(size.name, func(b *testing.B) {
+			b.SetBytes(int64(size.l))
+
+			dst, src := dst[:size.l], src[:size.l]
+
+			for i := 0; i < b.N; i++ {
+				fn(dst, src, 0x53)
+			}
+		})
+	}
+}
+
+func BenchmarkGaloisMulXOR(b *testing.B) {
+	benchmarkGalois(b, GaloisMulXOR)
+}
+
+func BenchmarkGaloisMulXORGo(b *testing.B) {
+	benchmarkGalois(b, func(dst, src []byte, c byte) int {
+		var t [32]byte
+		galoisTables(&t, c)
+
+		galoisMulXORGeneric(dst, src, &t)
+		return len(dst)
+	})
+}
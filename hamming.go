@@ -0,0 +1,163 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+func checkRowSize(query []byte, rowSize int) {
+	if rowSize <= 0 {
+		panic("bitwise: invalid row size")
+	}
+
+	if len(query) < rowSize {
+		panic("bitwise: query shorter than row size")
+	}
+}
+
+func hammingDistance(a, b []byte) uint32 {
+	var d int
+
+	i := 0
+	for ; i+8 <= len(a); i += 8 {
+		d += bits.OnesCount64(binary.LittleEndian.Uint64(a[i:]) ^ binary.LittleEndian.Uint64(b[i:]))
+	}
+
+	for ; i < len(a); i++ {
+		d += bits.OnesCount8(a[i] ^ b[i])
+	}
+
+	return uint32(d)
+}
+
+func onesCountGeneric(src []byte) uint64 {
+	var n int
+
+	i := 0
+	for ; i+8 <= len(src); i += 8 {
+		n += bits.OnesCount64(binary.LittleEndian.Uint64(src[i:]))
+	}
+
+	for ; i < len(src); i++ {
+		n += bits.OnesCount8(src[i])
+	}
+
+	return uint64(n)
+}
+
+func hammingGeneric(dst []uint32, query, rows []byte, rowSize int) {
+	query = query[:rowSize]
+
+	for i := range dst {
+		dst[i] = hammingDistance(query, rows[i*rowSize:(i+1)*rowSize])
+	}
+}
+
+// topKBatch is the number of rows whose distances TopK computes with
+// each call to HammingDistances.
+const topKBatch = 256
+
+// topKRow is a row index and its distance from the query.
+type topKRow struct {
+	i int
+	d uint32
+}
+
+func (a topKRow) less(b topKRow) bool {
+	return a.d < b.d || a.d == b.d && a.i < b.i
+}
+
+// topKHeap is a max-heap of rows.
+type topKHeap []topKRow
+
+func (h topKHeap) up(j int) {
+	for j > 0 {
+		p := (j - 1) / 2
+		if !h[p].less(h[j]) {
+			break
+		}
+
+		h[p], h[j] = h[j], h[p]
+		j = p
+	}
+}
+
+func (h topKHeap) down(j int) {
+	for {
+		c := 2*j + 1
+		if c >= len(h) {
+			break
+		}
+
+		if c+1 < len(h) && h[c].less(h[c+1]) {
+			c++
+		}
+
+		if !h[j].less(h[c]) {
+			break
+		}
+
+		h[j], h[c] = h[c], h[j]
+		j = c
+	}
+}
+
+// TopK returns the indices of the k rows nearest to query by Hamming
+// distance, ordered from nearest to furthest, where rows holds contiguous
+// rows of rowSize bytes. Equal distances are ordered by index.
+//
+// The distances are computed in small batches, so memory use depends on
+// k rather than on the number of rows.
+func TopK(query, rows []byte, rowSize, k int) []int {
+	checkRowSize(query, rowSize)
+
+	nrows := len(rows) / rowSize
+	if k > nrows {
+		k = nrows
+	}
+
+	if k <= 0 {
+		return nil
+	}
+
+	var dist [topKBatch]uint32
+
+	// top holds the k nearest rows seen so far.
+	top := make(topKHeap, 0, k)
+
+	for base := 0; base < nrows; base += topKBatch {
+		n := HammingDistances(dist[:], query, rows[base*rowSize:], rowSize)
+
+		for j, d := range dist[:n] {
+			r := topKRow{base + j, d}
+
+			switch {
+			case len(top) < k:
+				top = append(top, r)
+				top.up(len(top) - 1)
+			case r.less(top[0]):
+				top[0] = r
+				top.down(0)
+			}
+		}
+	}
+
+	// Repeatedly moving the furthest row to the end of the heap sorts
+	// it from nearest to furthest.
+	for end := k - 1; end > 0; end-- {
+		top[0], top[end] = top[end], top[0]
+		top[:end].down(0)
+	}
+
+	idx := make([]int, k)
+	for i, r := range top {
+		idx[i] = r.i
+	}
+
+	return idx
+}
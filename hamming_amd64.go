@@ -0,0 +1,67 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+// HammingDistances sets each element in according to dst[i] = popcount(query XOR row[i])
+// where rows holds contiguous rows of rowSize bytes. It returns the number of rows compared.
+func HammingDistances(dst []uint32, query, rows []byte, rowSize int) int {
+	checkRowSize(query, rowSize)
+
+	n := len(rows) / rowSize
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	switch {
+	case impl >= implAVX512 && hasAVX512VPOPCNTDQ && rowSize%64 == 0:
+		hammingAVX512(&dst[0], &query[0], &rows[0], uint64(rowSize), uint64(n))
+	case impl >= implAVX2 && rowSize%32 == 0:
+		hammingAVX2(&dst[0], &query[0], &rows[0], uint64(rowSize), uint64(n))
+	default:
+		hammingGeneric(dst[:n], query, rows, rowSize)
+	}
+
+	return n
+}
+
+// OnesCount returns the number of one bits in src.
+func OnesCount(src []byte) uint64 {
+	var n uint64
+	var i int
+
+	switch {
+	case impl >= implAVX512 && hasAVX512VPOPCNTDQ && len(src) >= 64:
+		i = len(src) &^ 63
+		n = onesCountAVX512(&src[0], uint64(i))
+	case impl >= implAVX2 && len(src) >= 32:
+		i = len(src) &^ 31
+		n = onesCountAVX2(&src[0], uint64(i))
+	}
+
+	return n + onesCountGeneric(src[i:])
+}
+
+// This function is implemented in bitwise_popcnt_amd64.s
+//go:noescape
+func hammingAVX2(dst *uint32, query, rows *byte, rowSize, nrows uint64)
+
+// This function is implemented in bitwise_popcnt_amd64.s
+//go:noescape
+func hammingAVX512(dst *uint32, query, rows *byte, rowSize, nrows uint64)
+
+// This function is implemented in bitwise_popcnt_amd64.s
+//go:noescape
+func onesCountAVX2(src *byte, len uint64) (n uint64)
+
+// This function is implemented in bitwise_popcnt_amd64.s
+//go:noescape
+func onesCountAVX512(src *byte, len uint64) (n uint64)
@@ -0,0 +1,72 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func testHammingASM(t *testing.T, fn func(dst *uint32, query, rows *byte, rowSize, nrows uint64), width int) {
+	testHamming(t, func(dst []uint32, query, rows []byte, rowSize int) int {
+		if rowSize%width != 0 {
+			return testHammingDistances(dst, query, rows, rowSize)
+		}
+
+		n := len(rows) / rowSize
+		if len(dst) < n {
+			n = len(dst)
+		}
+
+		fn(&dst[0], &query[0], &rows[0], uint64(rowSize), uint64(n))
+		return n
+	})
+}
+
+func TestHammingAVX2(t *testing.T) {
+	if !hasAVX2 {
+		t.Skip("AVX2 not supported")
+	}
+
+	testHammingASM(t, hammingAVX2, 32)
+}
+
+func TestHammingAVX512(t *testing.T) {
+	if !hasAVX512VPOPCNTDQ {
+		t.Skip("AVX-512 VPOPCNTDQ not supported")
+	}
+
+	testHammingASM(t, hammingAVX512, 64)
+}
+
+func testOnesCountASM(t *testing.T, fn func(src *byte, len uint64) uint64, width int) {
+	for l := width; l <= 16*width; l += width {
+		src := make([]byte, l)
+		rand.Read(src)
+
+		if n, expect := fn(&src[0], uint64(l)), testOnesCount(src); n != expect {
+			t.Errorf("len = %d: expected %d, got %d", l, expect, n)
+		}
+	}
+}
+
+func TestOnesCountAVX2(t *testing.T) {
+	if !hasAVX2 {
+		t.Skip("AVX2 not supported")
+	}
+
+	testOnesCountASM(t, onesCountAVX2, 32)
+}
+
+func TestOnesCountAVX512(t *testing.T) {
+	if !hasAVX512VPOPCNTDQ {
+		t.Skip("AVX-512 VPOPCNTDQ not supported")
+	}
+
+	testOnesCountASM(t, onesCountAVX512, 64)
+}
@@ -0,0 +1,27 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+// HammingDistances sets each element in according to dst[i] = popcount(query XOR row[i])
+// where rows holds contiguous rows of rowSize bytes. It returns the number of rows compared.
+func HammingDistances(dst []uint32, query, rows []byte, rowSize int) int {
+	checkRowSize(query, rowSize)
+
+	n := len(rows) / rowSize
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	hammingGeneric(dst[:n], query, rows, rowSize)
+	return n
+}
+
+// OnesCount returns the number of one bits in src.
+func OnesCount(src []byte) uint64 {
+	return onesCountGeneric(src)
+}
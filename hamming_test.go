@@ -0,0 +1,239 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/quick"
+)
+
+var hammingRowSizes = []int{1, 7, 32, 64, 96, 128}
+
+func testHammingDistances(dst []uint32, query, rows []byte, rowSize int) int {
+	n := len(rows) / rowSize
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	for i := 0; i < n; i++ {
+		var d uint32
+		for j := 0; j < rowSize; j++ {
+			x := query[j] ^ rows[i*rowSize+j]
+			for ; x != 0; x >>= 1 {
+				d += uint32(x & 1)
+			}
+		}
+
+		dst[i] = d
+	}
+
+	return n
+}
+
+func testHamming(t *testing.T, fn func(dst []uint32, query, rows []byte, rowSize int) int) {
+	for _, rowSize := range hammingRowSizes {
+		query := make([]byte, rowSize)
+		rand.Read(query)
+
+		rows := make([]byte, 37*rowSize+rowSize/2)
+		rand.Read(rows)
+
+		copy(rows[5*rowSize:], query)
+
+		d1 := make([]uint32, 40)
+		n1 := fn(d1, query, rows, rowSize)
+
+		d2 := make([]uint32, 40)
+		n2 := testHammingDistances(d2, query, rows, rowSize)
+
+		if n1 != n2 {
+			t.Errorf("rowSize = %d: expected %d rows, got %d", rowSize, n2, n1)
+		}
+
+		if !reflect.DeepEqual(d1, d2) {
+			t.Errorf("rowSize = %d: expected %v, got %v", rowSize, d2, d1)
+		}
+
+		if d1[5] != 0 {
+			t.Errorf("rowSize = %d: expected zero distance for identical row, got %d", rowSize, d1[5])
+		}
+	}
+}
+
+func TestHammingDistances(t *testing.T) {
+	testHamming(t, HammingDistances)
+}
+
+func TestHammingDistancesGeneric(t *testing.T) {
+	testHamming(t, func(dst []uint32, query, rows []byte, rowSize int) int {
+		n := len(rows) / rowSize
+		if len(dst) < n {
+			n = len(dst)
+		}
+
+		hammingGeneric(dst[:n], query, rows, rowSize)
+		return n
+	})
+}
+
+func testTopK(query, rows []byte, rowSize, k int) []int {
+	distances := make([]uint32, len(rows)/rowSize)
+	testHammingDistances(distances, query, rows, rowSize)
+
+	idx := make([]int, len(distances))
+	for i := range idx {
+		idx[i] = i
+	}
+
+	sort.SliceStable(idx, func(i, j int) bool {
+		return distances[idx[i]] < distances[idx[j]]
+	})
+
+	if k > len(idx) {
+		k = len(idx)
+	}
+
+	if k <= 0 {
+		return nil
+	}
+
+	return idx[:k]
+}
+
+func TestTopK(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for _, rowSize := range hammingRowSizes {
+			query := make([]byte, rowSize)
+			rand.Read(query)
+
+			for _, nrows := range []int{0, 1, 10, topKBatch, 3*topKBatch + 17} {
+				rows := make([]byte, nrows*rowSize+rowSize/2)
+				rand.Read(rows)
+
+				// Rows close to the query give many equal distances.
+				for i := 0; i < nrows; i += 3 {
+					copy(rows[i*rowSize:], query)
+					rows[i*rowSize+rand.Intn(rowSize)] ^= 1 << uint(rand.Intn(2))
+				}
+
+				for _, k := range []int{0, 1, 5, 100, nrows + 1} {
+					got, want := TopK(query, rows, rowSize, k), testTopK(query, rows, rowSize, k)
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("rowSize = %d, %d rows, k = %d: expected %v, got %v", rowSize, nrows, k, want, got)
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestTopKAllocs(t *testing.T) {
+	const rowSize = 64
+
+	query := make([]byte, rowSize)
+	rows := make([]byte, 1<<14*rowSize)
+	rand.Read(rows)
+
+	// Only the heap and the result are allocated, not the distances.
+	if n := testing.AllocsPerRun(10, func() {
+		TopK(query, rows, rowSize, 10)
+	}); n > 2 {
+		t.Errorf("expected at most 2 allocations, got %v", n)
+	}
+}
+
+func BenchmarkHammingDistances(b *testing.B) {
+	const nrows = 1 << 16
+
+	for _, rowSize := range []int{32, 64, 128} {
+		b.Run(fmt.Sprint(rowSize*8), func(b *testing.B) {
+			query := make([]byte, rowSize)
+			rand.Read(query)
+
+			rows := make([]byte, nrows*rowSize)
+			rand.Read(rows)
+
+			dst := make([]uint32, nrows)
+
+			b.SetBytes(int64(len(rows)))
+
+			for i := 0; i < b.N; i++ {
+				HammingDistances(dst, query, rows, rowSize)
+			}
+		})
+	}
+}
+
+func BenchmarkTopK(b *testing.B) {
+	const nrows, rowSize = 1 << 16, 64
+
+	query := make([]byte, rowSize)
+	rand.Read(query)
+
+	rows := make([]byte, nrows*rowSize)
+	rand.Read(rows)
+
+	b.SetBytes(int64(len(rows)))
+
+	for i := 0; i < b.N; i++ {
+		TopK(query, rows, rowSize, 10)
+	}
+}
+
+func testOnesCount(src []byte) uint64 {
+	var n uint64
+	for _, b := range src {
+		for ; b != 0; b >>= 1 {
+			n += uint64(b & 1)
+		}
+	}
+
+	return n
+}
+
+func TestOnesCount(t *testing.T) {
+	for l := 0; l < 300; l++ {
+		src := make([]byte, l)
+		rand.Read(src)
+
+		if n, expect := OnesCount(src), testOnesCount(src); n != expect {
+			t.Errorf("len = %d: expected %d, got %d", l, expect, n)
+		}
+
+		if n, expect := onesCountGeneric(src), testOnesCount(src); n != expect {
+			t.Errorf("len = %d: generic expected %d, got %d", l, expect, n)
+		}
+	}
+
+	if err := quick.CheckEqual(testOnesCount, OnesCount, &quick.Config{
+		MaxCountScale: 100,
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func BenchmarkOnesCount(b *testing.B) {
+	maxSize := benchSizes[len(benchSizes)-1]
+
+	src := make([]byte, maxSize.l)
+	rand.Read(src)
+
+	for _, size := range benchSizes {
+		b.Run(size.name, func(b *testing.B) {
+			b.SetBytes(int64(size.l))
+
+			src := src[:size.l]
+
+			for i := 0; i < b.N; i++ {
+				OnesCount(src)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"fmt"
+	"os"
+)
+
+// implementation identifies a tier of kernels used by the bitwise
+// operations. Each architecture declares its own tiers, along with
+// implNames and supported, in increasing order of the CPU features they
+// require.
+type implementation int
+
+func (i implementation) String() string {
+	return implNames[i]
+}
+
+// impl is the implementation in use. It is a package level variable so
+// that it is initialised after the CPU feature variables it depends on.
+var impl = defaultImplementation()
+
+func defaultImplementation() implementation {
+	// An unknown or unsupported implementation is ignored so that
+	// the variable may be shared between machines.
+	if name := os.Getenv("BITWISE_IMPL"); name != "" {
+		if i, err := lookupImplementation(name); err == nil {
+			return i
+		}
+	}
+
+	return bestImplementation()
+}
+
+func bestImplementation() implementation {
+	for i := implementation(len(implNames) - 1); i > implGeneric; i-- {
+		if i.supported() {
+			return i
+		}
+	}
+
+	return implGeneric
+}
+
+// Implementation returns the name of the implementation used by the
+// bitwise operations, one of generic, sse2, avx2 or avx512 on amd64
+// and generic or neon on arm64. Only generic is available on other
+// architectures or when built with the purego tag.
+func Implementation() string {
+	return impl.String()
+}
+
+// SetImplementation forces the bitwise operations to use the named
+// implementation, as returned by Implementation. The empty string
+// selects the best implementation supported by the CPU.
+//
+// The implementation may also be chosen by setting the BITWISE_IMPL
+// environment variable.
+//
+// SetImplementation is intended for testing and benchmarking, it must
+// not be called concurrently with any other function in this package.
+func SetImplementation(name string) error {
+	if name == "" {
+		impl = bestImplementation()
+		return nil
+	}
+
+	i, err := lookupImplementation(name)
+	if err != nil {
+		return err
+	}
+
+	impl = i
+	return nil
+}
+
+func lookupImplementation(name string) (implementation, error) {
+	for i, n := range implNames {
+		if n != name {
+			continue
+		}
+
+		if !implementation(i).supported() {
+			return implGeneric, fmt.Errorf("bitwise: implementation %q is not supported by the CPU", name)
+		}
+
+		return implementation(i), nil
+	}
+
+	return implGeneric, fmt.Errorf("bitwise: unknown implementation %q", name)
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSetImplementation(t *testing.T) {
+	best := Implementation()
+	defer SetImplementation(best)
+
+	if err := SetImplementation("generic"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Implementation(); got != "generic" {
+		t.Errorf("expected generic, got %s", got)
+	}
+
+	if err := SetImplementation("mmx"); err == nil {
+		t.Error("SetImplementation succeeded for unknown implementation")
+	}
+
+	if got := Implementation(); got != "generic" {
+		t.Errorf("failed SetImplementation changed implementation to %s", got)
+	}
+
+	if err := SetImplementation(""); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := Implementation(); got != best {
+		t.Errorf("expected %s, got %s", best, got)
+	}
+}
+
+func TestImplementationEnv(t *testing.T) {
+	defer os.Setenv("BITWISE_IMPL", os.Getenv("BITWISE_IMPL"))
+
+	os.Setenv("BITWISE_IMPL", "generic")
+	if got := defaultImplementation(); got != implGeneric {
+		t.Errorf("expected generic, got %s", got)
+	}
+
+	os.Setenv("BITWISE_IMPL", "mmx")
+	if got, want := defaultImplementation(), bestImplementation(); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
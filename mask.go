@@ -0,0 +1,57 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+// maskBias is xored with bytes so that unsigned bytes compare the same
+// as the signed bytes compared by PCMPGTB.
+const maskBias = 0x80
+
+func maskLen(dst, src []byte) int {
+	n := len(src)
+	if 8*len(dst) < n {
+		n = 8 * len(dst)
+	}
+
+	return n
+}
+
+// maskGeneric sets bit i%8 of dst[i/8] if fn(src[i]) is true. Any bits
+// of the final byte of dst beyond len(src) are cleared.
+func maskGeneric(dst, src []byte, fn func(c byte) bool) {
+	for i := range src {
+		if i%8 == 0 {
+			dst[i/8] = 0
+		}
+
+		if fn(src[i]) {
+			dst[i/8] |= 1 << uint(i%8)
+		}
+	}
+}
+
+func equalMaskGeneric(dst, src []byte, v byte) {
+	maskGeneric(dst, src, func(c byte) bool {
+		return c == v
+	})
+}
+
+func lessMaskGeneric(dst, src []byte, v byte) {
+	maskGeneric(dst, src, func(c byte) bool {
+		return c < v
+	})
+}
+
+func greaterMaskGeneric(dst, src []byte, v byte) {
+	maskGeneric(dst, src, func(c byte) bool {
+		return c > v
+	})
+}
+
+func rangeMaskGeneric(dst, src []byte, lo, hi byte) {
+	maskGeneric(dst, src, func(c byte) bool {
+		return lo <= c && c <= hi
+	})
+}
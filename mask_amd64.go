@@ -0,0 +1,126 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+// EqualMask sets bit i%8 of dst[i/8] if src[i] == v. Any bits of the
+// final byte of dst beyond the end of src are cleared. It returns the
+// number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func EqualMask(dst, src []byte, v byte) int {
+	n := maskLen(dst, src)
+
+	var i int
+	switch {
+	case impl >= implAVX2 && n >= 32:
+		i = n &^ 31
+		equalMaskAVX2(&dst[0], &src[0], uint64(i), uint64(v))
+	case impl >= implSSE2 && n >= 16:
+		i = n &^ 15
+		equalMaskASM(&dst[0], &src[0], uint64(i), uint64(v))
+	}
+
+	equalMaskGeneric(dst[i/8:], src[i:n], v)
+	return n
+}
+
+// LessMask sets bit i%8 of dst[i/8] if src[i] < v. Any bits of the
+// final byte of dst beyond the end of src are cleared. It returns the
+// number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func LessMask(dst, src []byte, v byte) int {
+	n := maskLen(dst, src)
+
+	var i int
+	switch {
+	case impl >= implAVX2 && n >= 32:
+		i = n &^ 31
+		lessMaskAVX2(&dst[0], &src[0], uint64(i), uint64(v^maskBias))
+	case impl >= implSSE2 && n >= 16:
+		i = n &^ 15
+		lessMaskASM(&dst[0], &src[0], uint64(i), uint64(v^maskBias))
+	}
+
+	lessMaskGeneric(dst[i/8:], src[i:n], v)
+	return n
+}
+
+// GreaterMask sets bit i%8 of dst[i/8] if src[i] > v. Any bits of the
+// final byte of dst beyond the end of src are cleared. It returns the
+// number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func GreaterMask(dst, src []byte, v byte) int {
+	n := maskLen(dst, src)
+
+	var i int
+	switch {
+	case impl >= implAVX2 && n >= 32:
+		i = n &^ 31
+		greaterMaskAVX2(&dst[0], &src[0], uint64(i), uint64(v^maskBias))
+	case impl >= implSSE2 && n >= 16:
+		i = n &^ 15
+		greaterMaskASM(&dst[0], &src[0], uint64(i), uint64(v^maskBias))
+	}
+
+	greaterMaskGeneric(dst[i/8:], src[i:n], v)
+	return n
+}
+
+// RangeMask sets bit i%8 of dst[i/8] if lo <= src[i] <= hi. Any bits of
+// the final byte of dst beyond the end of src are cleared. It returns
+// the number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func RangeMask(dst, src []byte, lo, hi byte) int {
+	n := maskLen(dst, src)
+
+	var i int
+	switch {
+	case lo > hi:
+		// No byte is in the range, rangeMaskGeneric clears dst.
+	case impl >= implAVX2 && n >= 32:
+		i = n &^ 31
+		rangeMaskAVX2(&dst[0], &src[0], uint64(i), uint64(lo), uint64((hi-lo)^maskBias))
+	case impl >= implSSE2 && n >= 16:
+		i = n &^ 15
+		rangeMaskASM(&dst[0], &src[0], uint64(i), uint64(lo), uint64((hi-lo)^maskBias))
+	}
+
+	rangeMaskGeneric(dst[i/8:], src[i:n], lo, hi)
+	return n
+}
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func equalMaskASM(dst, src *byte, len, v uint64)
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func equalMaskAVX2(dst, src *byte, len, v uint64)
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func lessMaskASM(dst, src *byte, len, v uint64)
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func lessMaskAVX2(dst, src *byte, len, v uint64)
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func greaterMaskASM(dst, src *byte, len, v uint64)
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func greaterMaskAVX2(dst, src *byte, len, v uint64)
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func rangeMaskASM(dst, src *byte, len, lo, hi uint64)
+
+// This function is implemented in bitwise_mask_amd64.s
+//go:noescape
+func rangeMaskAVX2(dst, src *byte, len, lo, hi uint64)
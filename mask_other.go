@@ -0,0 +1,48 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+// EqualMask sets bit i%8 of dst[i/8] if src[i] == v. Any bits of the
+// final byte of dst beyond the end of src are cleared. It returns the
+// number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func EqualMask(dst, src []byte, v byte) int {
+	n := maskLen(dst, src)
+	equalMaskGeneric(dst, src[:n], v)
+	return n
+}
+
+// LessMask sets bit i%8 of dst[i/8] if src[i] < v. Any bits of the
+// final byte of dst beyond the end of src are cleared. It returns the
+// number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func LessMask(dst, src []byte, v byte) int {
+	n := maskLen(dst, src)
+	lessMaskGeneric(dst, src[:n], v)
+	return n
+}
+
+// GreaterMask sets bit i%8 of dst[i/8] if src[i] > v. Any bits of the
+// final byte of dst beyond the end of src are cleared. It returns the
+// number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func GreaterMask(dst, src []byte, v byte) int {
+	n := maskLen(dst, src)
+	greaterMaskGeneric(dst, src[:n], v)
+	return n
+}
+
+// RangeMask sets bit i%8 of dst[i/8] if lo <= src[i] <= hi. Any bits of
+// the final byte of dst beyond the end of src are cleared. It returns
+// the number of bytes of src compared, the smaller of len(src) and
+// 8*len(dst).
+func RangeMask(dst, src []byte, lo, hi byte) int {
+	n := maskLen(dst, src)
+	rangeMaskGeneric(dst, src[:n], lo, hi)
+	return n
+}
@@ -0,0 +1,129 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+type maskTest struct {
+	name   string
+	fn     func(dst, src []byte, lo, hi byte) int
+	testFn func(c, lo, hi byte) bool
+}
+
+var maskTests = []maskTest{
+	{"Equal", func(dst, src []byte, v, _ byte) int {
+		return EqualMask(dst, src, v)
+	}, func(c, v, _ byte) bool {
+		return c == v
+	}},
+	{"Less", func(dst, src []byte, v, _ byte) int {
+		return LessMask(dst, src, v)
+	}, func(c, v, _ byte) bool {
+		return c < v
+	}},
+	{"Greater", func(dst, src []byte, v, _ byte) int {
+		return GreaterMask(dst, src, v)
+	}, func(c, v, _ byte) bool {
+		return c > v
+	}},
+	{"Range", RangeMask, func(c, lo, hi byte) bool {
+		return lo <= c && c <= hi
+	}},
+}
+
+func testMaskBytes(dst, src []byte, lo, hi byte, fn func(c, lo, hi byte) bool) int {
+	n := len(src)
+	if 8*len(dst) < n {
+		n = 8 * len(dst)
+	}
+
+	for i := 0; i < (n+7)/8; i++ {
+		dst[i] = 0
+	}
+
+	for i, c := range src[:n] {
+		if fn(c, lo, hi) {
+			dst[i/8] |= 1 << uint(i%8)
+		}
+	}
+
+	return n
+}
+
+func TestCompareMasks(t *testing.T) {
+	values := [][2]byte{{0, 0}, {0, 255}, {7, 100}, {100, 7}, {127, 128}, {128, 200}, {255, 255}}
+
+	for _, mt := range maskTests {
+		mt := mt
+		t.Run(mt.name, func(t *testing.T) {
+			testImplementations(t, func(t *testing.T) {
+				for size := 0; size <= 300; size++ {
+					src := make([]byte, size)
+					rand.Read(src)
+
+					for i := range src {
+						if i%3 == 0 {
+							src[i] = values[i%len(values)][i%2]
+						}
+					}
+
+					for _, v := range values {
+						d1 := bytes.Repeat([]byte{0xff}, (size+7)/8+1)
+						n1 := mt.fn(d1, src, v[0], v[1])
+
+						d2 := bytes.Repeat([]byte{0xff}, (size+7)/8+1)
+						n2 := testMaskBytes(d2, src, v[0], v[1], mt.testFn)
+
+						if n1 != n2 {
+							t.Errorf("size %d: expected %d bytes, got %d", size, n2, n1)
+						}
+
+						if !bytes.Equal(d1, d2) {
+							t.Errorf("size %d with values %v: expected %x, got %x", size, v, d2, d1)
+						}
+					}
+
+					short := make([]byte, size/16)
+					if n := mt.fn(short, src, 7, 100); n != 8*len(short) {
+						t.Errorf("size %d: expected %d bytes with short dst, got %d", size, 8*len(short), n)
+					}
+				}
+			})
+		})
+	}
+}
+
+func benchmarkMask(b *testing.B, fn func(dst, src []byte, lo, hi byte) int) {
+	src := make([]byte, 16*1024)
+	rand.Read(src)
+
+	dst := make([]byte, len(src)/8)
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		fn(dst, src, 7, 100)
+	}
+}
+
+func BenchmarkEqualMask(b *testing.B) {
+	benchmarkMask(b, maskTests[0].fn)
+}
+
+func BenchmarkLessMask(b *testing.B) {
+	benchmarkMask(b, maskTests[1].fn)
+}
+
+func BenchmarkGreaterMask(b *testing.B) {
+	benchmarkMask(b, maskTests[2].fn)
+}
+
+func BenchmarkRangeMask(b *testing.B) {
+	benchmarkMask(b, RangeMask)
+}
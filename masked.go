@@ -0,0 +1,56 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+// CopyMasked sets dst[i] = src[i] for each i where bit i%8 of mask[i/8]
+// is set, in LSBFirst order, and leaves the other bytes of dst
+// unmodified. It returns the number of bytes considered, the smallest of
+// len(dst), len(src) and 8*len(mask).
+func CopyMasked(dst, src, mask []byte) int {
+	n := maskLen(mask, dst)
+	if len(src) < n {
+		n = len(src)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst[:n], src[:n], src[:n])
+
+	copyMasked(dst[:n], src[:n], mask)
+	return n
+}
+
+// FillMasked sets dst[i] = v for each i where bit i%8 of mask[i/8] is
+// set, in LSBFirst order, and leaves the other bytes of dst unmodified.
+// It returns the number of bytes considered, the smaller of len(dst)
+// and 8*len(mask).
+func FillMasked(dst, mask []byte, v byte) int {
+	n := maskLen(mask, dst)
+	if n == 0 {
+		return 0
+	}
+
+	fillMasked(dst[:n], mask, v)
+	return n
+}
+
+func copyMaskedGeneric(dst, src, mask []byte) {
+	for i := range dst {
+		if mask[i/8]&(1<<uint(i%8)) != 0 {
+			dst[i] = src[i]
+		}
+	}
+}
+
+func fillMaskedGeneric(dst, mask []byte, v byte) {
+	for i := range dst {
+		if mask[i/8]&(1<<uint(i%8)) != 0 {
+			dst[i] = v
+		}
+	}
+}
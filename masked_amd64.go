@@ -0,0 +1,56 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+func copyMasked(dst, src, mask []byte) {
+	n := len(dst)
+
+	var i int
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		i = n &^ 63
+		copyMaskedAVX512(&dst[0], &src[0], &mask[0], uint64(i))
+	case impl >= implSSE2 && hasSSSE3 && n >= 16:
+		i = n &^ 15
+		copyMaskedASM(&dst[0], &src[0], &mask[0], uint64(i))
+	}
+
+	copyMaskedGeneric(dst[i:], src[i:], mask[i/8:])
+}
+
+func fillMasked(dst, mask []byte, v byte) {
+	n := len(dst)
+
+	var i int
+	switch {
+	case impl >= implAVX512 && n >= 64:
+		i = n &^ 63
+		fillMaskedAVX512(&dst[0], &mask[0], uint64(i), uint64(v))
+	case impl >= implSSE2 && hasSSSE3 && n >= 16:
+		i = n &^ 15
+		fillMaskedASM(&dst[0], &mask[0], uint64(i), uint64(v))
+	}
+
+	fillMaskedGeneric(dst[i:], mask[i/8:], v)
+}
+
+// This function is implemented in bitwise_masked_amd64.s
+//go:noescape
+func copyMaskedASM(dst, src, mask *byte, len uint64)
+
+// This function is implemented in bitwise_masked_amd64.s
+//go:noescape
+func fillMaskedASM(dst, mask *byte, len, v uint64)
+
+// This function is implemented in bitwise_masked_amd64.s
+//go:noescape
+func copyMaskedAVX512(dst, src, mask *byte, len uint64)
+
+// This function is implemented in bitwise_masked_amd64.s
+//go:noescape
+func fillMaskedAVX512(dst, mask *byte, len, v uint64)
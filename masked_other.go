@@ -0,0 +1,16 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+func copyMasked(dst, src, mask []byte) {
+	copyMaskedGeneric(dst, src, mask)
+}
+
+func fillMasked(dst, mask []byte, v byte) {
+	fillMaskedGeneric(dst, mask, v)
+}
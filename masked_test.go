@@ -0,0 +1,163 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"runtime"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCopyMasked(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for size := 0; size <= 300; size++ {
+			src := make([]byte, size)
+			rand.Read(src)
+
+			for _, mask := range testMasks(size) {
+				d1 := make([]byte, size)
+				rand.Read(d1)
+
+				d2 := append([]byte(nil), d1...)
+				for i := range d2 {
+					if mask[i/8]>>uint(i%8)&1 != 0 {
+						d2[i] = src[i]
+					}
+				}
+
+				if n := CopyMasked(d1, src, mask); n != size {
+					t.Errorf("size %d: expected %d bytes, got %d", size, size, n)
+				}
+
+				if !bytes.Equal(d1, d2) {
+					t.Errorf("size %d: expected %x, got %x", size, d2, d1)
+				}
+			}
+		}
+	})
+}
+
+func TestFillMasked(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for size := 0; size <= 300; size++ {
+			for _, mask := range testMasks(size) {
+				d1 := make([]byte, size)
+				rand.Read(d1)
+
+				d2 := append([]byte(nil), d1...)
+				for i := range d2 {
+					if mask[i/8]>>uint(i%8)&1 != 0 {
+						d2[i] = 0x5a
+					}
+				}
+
+				if n := FillMasked(d1, mask, 0x5a); n != size {
+					t.Errorf("size %d: expected %d bytes, got %d", size, size, n)
+				}
+
+				if !bytes.Equal(d1, d2) {
+					t.Errorf("size %d: expected %x, got %x", size, d2, d1)
+				}
+			}
+		}
+	})
+}
+
+// TestFillMaskedConcurrent checks that the bytes not selected by the mask
+// are not written, so that complementary masks may be used concurrently
+// on the same buffer.
+func TestFillMaskedConcurrent(t *testing.T) {
+	if runtime.GOMAXPROCS(0) < 2 {
+		t.Skip("needs GOMAXPROCS of at least 2")
+	}
+
+	testImplementations(t, func(t *testing.T) {
+		buf := make([]byte, 1024)
+		even := bytes.Repeat([]byte{0x55}, len(buf)/8)
+		odd := bytes.Repeat([]byte{0xaa}, len(buf)/8)
+
+		for round := 0; round < 100; round++ {
+			for i := range buf {
+				buf[i] = 0
+			}
+
+			var fills, stop int32
+			done := make(chan struct{})
+
+			go func() {
+				for atomic.LoadInt32(&stop) == 0 {
+					FillMasked(buf, even, 1)
+					atomic.AddInt32(&fills, 1)
+				}
+
+				close(done)
+			}()
+
+			for atomic.LoadInt32(&fills) == 0 {
+				runtime.Gosched()
+			}
+
+			FillMasked(buf, odd, 2)
+
+			// Any fill of the even bytes that was in progress has
+			// finished once two more have completed.
+			for n := atomic.LoadInt32(&fills); atomic.LoadInt32(&fills) < n+2; {
+				runtime.Gosched()
+			}
+
+			atomic.StoreInt32(&stop, 1)
+			<-done
+
+			for i := 1; i < len(buf); i += 2 {
+				if buf[i] != 2 {
+					t.Fatalf("round %d: byte %d was overwritten with %d", round, i, buf[i])
+				}
+			}
+		}
+	})
+}
+
+func TestMaskedLength(t *testing.T) {
+	dst := make([]byte, 100)
+	mask := bytes.Repeat([]byte{0xff}, 3)
+
+	if n := CopyMasked(dst, bytes.Repeat([]byte{1}, 50), mask); n != 24 {
+		t.Errorf("CopyMasked: expected 24 bytes, got %d", n)
+	}
+
+	if n := FillMasked(dst, mask, 2); n != 24 {
+		t.Errorf("FillMasked: expected 24 bytes, got %d", n)
+	}
+
+	if dst[23] != 2 || dst[24] != 0 {
+		t.Errorf("FillMasked modified the wrong bytes: %x", dst[:32])
+	}
+}
+
+func BenchmarkCopyMasked(b *testing.B) {
+	src := make([]byte, 16*1024)
+	rand.Read(src)
+
+	mask := testMasks(len(src))[2]
+	dst := make([]byte, len(src))
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		CopyMasked(dst, src, mask)
+	}
+}
+
+func BenchmarkFillMasked(b *testing.B) {
+	dst := make([]byte, 16*1024)
+	mask := testMasks(len(dst))[2]
+	b.SetBytes(int64(len(dst)))
+
+	for i := 0; i < b.N; i++ {
+		FillMasked(dst, mask, 0x5a)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import "unsafe"
+
+// Overlaps reports whether x and y share any memory.
+//
+// The operations in this package allow dst to be exactly the same
+// slice as a source, in which case it is updated in place, but they
+// panic if dst otherwise overlaps a source. The kernels for different
+// architectures process buffers in different orders, so the result of
+// a partial overlap would not be well defined. Expand is the exception,
+// it moves bytes towards the end of the buffer and panics on any overlap.
+func Overlaps(x, y []byte) bool {
+	if len(x) == 0 || len(y) == 0 {
+		return false
+	}
+
+	xp, yp := uintptr(unsafe.Pointer(&x[0])), uintptr(unsafe.Pointer(&y[0]))
+	return xp < yp+uintptr(len(y)) && yp < xp+uintptr(len(x))
+}
+
+// inexactOverlap reports whether x and y overlap other than by starting
+// at the same address.
+func inexactOverlap(x, y []byte) bool {
+	return Overlaps(x, y) && &x[0] != &y[0]
+}
+
+// checkOverlap panics if the bytes of dst that an operation would write
+// overlap a or b other than exactly.
+func checkOverlap(dst, a, b []byte) {
+	n := minLen(dst, a, b)
+	if inexactOverlap(dst[:n], a[:n]) || inexactOverlap(dst[:n], b[:n]) {
+		panic("bitwise: invalid buffer overlap")
+	}
+}
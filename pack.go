@@ -0,0 +1,89 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import "unsafe"
+
+// BitOrder is the order of the bits within each byte of a packed
+// bitmap.
+type BitOrder int
+
+const (
+	// LSBFirst stores the first of each group of eight values in the
+	// least significant bit. It is the order used by EqualMask and
+	// the other mask operations.
+	LSBFirst BitOrder = iota
+
+	// MSBFirst stores the first of each group of eight values in the
+	// most significant bit.
+	MSBFirst
+)
+
+// PackBools sets bit i of dst, in the given order, if src[i] is true.
+// Any bits of the final byte of dst beyond the end of src are cleared.
+// It returns the number of elements of src packed, the smaller of
+// len(src) and 8*len(dst).
+func PackBools(dst []byte, src []bool, order BitOrder) int {
+	return packBytes(dst, boolBytes(src), order)
+}
+
+// PackBytes sets bit i of dst, in the given order, if src[i] is
+// non-zero. Any bits of the final byte of dst beyond the end of src are
+// cleared. It returns the number of bytes of src packed, the smaller of
+// len(src) and 8*len(dst).
+func PackBytes(dst, src []byte, order BitOrder) int {
+	return packBytes(dst, src, order)
+}
+
+// UnpackBools sets dst[i] to bit i of src, in the given order. It
+// returns the number of elements of dst set, the smaller of len(dst)
+// and 8*len(src).
+func UnpackBools(dst []bool, src []byte, order BitOrder) int {
+	return unpackBytes(boolBytes(dst), src, order)
+}
+
+// UnpackBytes sets dst[i] to one if bit i of src, in the given order,
+// is set and to zero otherwise. It returns the number of bytes of dst
+// set, the smaller of len(dst) and 8*len(src).
+func UnpackBytes(dst, src []byte, order BitOrder) int {
+	return unpackBytes(dst, src, order)
+}
+
+func boolBytes(b []bool) []byte {
+	return *(*[]byte)(unsafe.Pointer(&b))
+}
+
+// bitMask returns the bit of a packed byte that holds element i of its
+// group of eight.
+func bitMask(i int, order BitOrder) byte {
+	if order == MSBFirst {
+		return 0x80 >> uint(i%8)
+	}
+
+	return 1 << uint(i%8)
+}
+
+func packGeneric(dst, src []byte, order BitOrder) {
+	for i, c := range src {
+		if i%8 == 0 {
+			dst[i/8] = 0
+		}
+
+		if c != 0 {
+			dst[i/8] |= bitMask(i, order)
+		}
+	}
+}
+
+func unpackGeneric(dst, src []byte, order BitOrder) {
+	for i := range dst {
+		if src[i/8]&bitMask(i, order) != 0 {
+			dst[i] = 1
+		} else {
+			dst[i] = 0
+		}
+	}
+}
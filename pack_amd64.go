@@ -0,0 +1,63 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+func packBytes(dst, src []byte, order BitOrder) int {
+	n := maskLen(dst, src)
+
+	var i int
+	switch {
+	case n < 16 || impl < implSSE2:
+	case order != MSBFirst:
+		i = n &^ 15
+		packLSBASM(&dst[0], &src[0], uint64(i))
+	case hasSSSE3:
+		i = n &^ 15
+		packMSBASM(&dst[0], &src[0], uint64(i))
+	}
+
+	packGeneric(dst[i/8:], src[i:n], order)
+	return n
+}
+
+func unpackBytes(dst, src []byte, order BitOrder) int {
+	n := len(dst)
+	if 8*len(src) < n {
+		n = 8 * len(src)
+	}
+
+	var i int
+	if impl >= implSSE2 && hasSSSE3 && n >= 16 {
+		i = n &^ 15
+
+		if order == MSBFirst {
+			unpackMSBASM(&dst[0], &src[0], uint64(i))
+		} else {
+			unpackLSBASM(&dst[0], &src[0], uint64(i))
+		}
+	}
+
+	unpackGeneric(dst[i:n], src[i/8:], order)
+	return n
+}
+
+// This function is implemented in bitwise_pack_amd64.s
+//go:noescape
+func packLSBASM(dst, src *byte, len uint64)
+
+// This function is implemented in bitwise_pack_amd64.s
+//go:noescape
+func packMSBASM(dst, src *byte, len uint64)
+
+// This function is implemented in bitwise_pack_amd64.s
+//go:noescape
+func unpackLSBASM(dst, src *byte, len uint64)
+
+// This function is implemented in bitwise_pack_amd64.s
+//go:noescape
+func unpackMSBASM(dst, src *byte, len uint64)
@@ -0,0 +1,25 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+func packBytes(dst, src []byte, order BitOrder) int {
+	n := maskLen(dst, src)
+
+	packGeneric(dst, src[:n], order)
+	return n
+}
+
+func unpackBytes(dst, src []byte, order BitOrder) int {
+	n := len(dst)
+	if 8*len(src) < n {
+		n = 8 * len(src)
+	}
+
+	unpackGeneric(dst[:n], src, order)
+	return n
+}
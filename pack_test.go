@@ -0,0 +1,131 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func testBit(i int, order BitOrder) byte {
+	if order == MSBFirst {
+		return 1 << uint(7-i%8)
+	}
+
+	return 1 << uint(i%8)
+}
+
+func TestPack(t *testing.T) {
+	testImplementations(t, func(t *testing.T) {
+		for _, order := range []BitOrder{LSBFirst, MSBFirst} {
+			for size := 0; size <= 300; size++ {
+				src := make([]byte, size)
+				rand.Read(src)
+
+				bools := make([]bool, size)
+				for i := range src {
+					if i%3 == 0 {
+						src[i] = 0
+					}
+
+					bools[i] = src[i] != 0
+				}
+
+				// The final byte of dst is beyond the packed bits and
+				// must not be modified.
+				want := make([]byte, (size+7)/8, (size+7)/8+1)
+				for i, c := range src {
+					if c != 0 {
+						want[i/8] |= testBit(i, order)
+					}
+				}
+				want = append(want, 0xff)
+
+				d1 := bytes.Repeat([]byte{0xff}, len(want))
+				if n := PackBytes(d1, src, order); n != size {
+					t.Errorf("PackBytes: expected %d bytes, got %d", size, n)
+				}
+
+				if !bytes.Equal(d1, want) {
+					t.Errorf("PackBytes failed for size %d and order %d: expected %x, got %x",
+						size, order, want, d1)
+				}
+
+				d2 := bytes.Repeat([]byte{0xff}, len(want))
+				if n := PackBools(d2, bools, order); n != size {
+					t.Errorf("PackBools: expected %d bools, got %d", size, n)
+				}
+
+				if !bytes.Equal(d2, want) {
+					t.Errorf("PackBools failed for size %d and order %d: expected %x, got %x",
+						size, order, want, d2)
+				}
+
+				u1 := make([]byte, size)
+				if n := UnpackBytes(u1, want, order); n != size {
+					t.Errorf("UnpackBytes: expected %d bytes, got %d", size, n)
+				}
+
+				for i := range src {
+					if src[i] != 0 {
+						src[i] = 1
+					}
+				}
+
+				if !bytes.Equal(u1, src) {
+					t.Errorf("UnpackBytes failed for size %d and order %d", size, order)
+				}
+
+				u2 := make([]bool, size)
+				if n := UnpackBools(u2, want, order); n != size {
+					t.Errorf("UnpackBools: expected %d bools, got %d", size, n)
+				}
+
+				if !reflect.DeepEqual(u2, bools) {
+					t.Errorf("UnpackBools failed for size %d and order %d", size, order)
+				}
+			}
+		}
+	})
+}
+
+func TestPackLength(t *testing.T) {
+	src := make([]byte, 100)
+
+	if n := PackBytes(make([]byte, 3), src, LSBFirst); n != 24 {
+		t.Errorf("PackBytes: expected 24 bytes, got %d", n)
+	}
+
+	if n := UnpackBytes(src, make([]byte, 3), LSBFirst); n != 24 {
+		t.Errorf("UnpackBytes: expected 24 bytes, got %d", n)
+	}
+}
+
+func BenchmarkPackBytes(b *testing.B) {
+	src := make([]byte, 16*1024)
+	rand.Read(src)
+
+	dst := make([]byte, len(src)/8)
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		PackBytes(dst, src, LSBFirst)
+	}
+}
+
+func BenchmarkUnpackBytes(b *testing.B) {
+	src := make([]byte, 2*1024)
+	rand.Read(src)
+
+	dst := make([]byte, 8*len(src))
+	b.SetBytes(int64(len(dst)))
+
+	for i := 0; i < b.N; i++ {
+		UnpackBytes(dst, src, LSBFirst)
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// DefaultParallelThreshold is the size, in bytes, below which a Parallel
+// operation with no Threshold set is performed on a single goroutine.
+const DefaultParallelThreshold = 1 << 20
+
+// cacheLineSize is the alignment of the chunks processed by each
+// goroutine, it avoids two goroutines writing to the same cache line.
+const cacheLineSize = 64
+
+// Parallel performs bitwise operations on large buffers by splitting
+// them into chunks that are processed concurrently. The zero value is
+// ready to use.
+type Parallel struct {
+	// Workers is the maximum number of goroutines to use. If zero,
+	// runtime.GOMAXPROCS(0) is used.
+	Workers int
+
+	// Threshold is the size, in bytes, below which the operation is
+	// performed on the calling goroutine. If zero,
+	// DefaultParallelThreshold is used.
+	Threshold int
+}
+
+// run calls fn for each chunk [i, j) of the first n bytes of dst. The
+// caller must have checked the whole buffers for overlap, as a panic in
+// another goroutine cannot be recovered and a chunk may not overlap its
+// sources even though the buffers do.
+func (p Parallel) run(dst []byte, n int, fn func(i, j int)) {
+	workers := p.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	threshold := p.Threshold
+	if threshold <= 0 {
+		threshold = DefaultParallelThreshold
+	}
+
+	if n < threshold || workers == 1 || n < 2*cacheLineSize {
+		fn(0, n)
+		return
+	}
+
+	// Chunk boundaries are placed on cache line boundaries of dst. The
+	// chunks are sized as if dst started skew bytes earlier, so the
+	// shortened first chunk counts towards the workers.
+	skew := int(uintptr(unsafe.Pointer(&dst[0])) % cacheLineSize)
+
+	chunk := (n + skew + workers - 1) / workers
+	chunk = (chunk + cacheLineSize - 1) &^ (cacheLineSize - 1)
+
+	first := chunk - skew
+	if first > n {
+		first = n
+	}
+
+	var wg sync.WaitGroup
+
+	for i := first; i < n; i += chunk {
+		j := i + chunk
+		if j > n {
+			j = n
+		}
+
+		wg.Add(1)
+		go func(i, j int) {
+			fn(i, j)
+			wg.Done()
+		}(i, j)
+	}
+
+	fn(0, first)
+	wg.Wait()
+}
+
+func minLen(dst, a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	return n
+}
+
+// XOR sets each element in according to dst[i] = a[i] XOR b[i]
+func (p Parallel) XOR(dst, a, b []byte) int {
+	n := minLen(dst, a, b)
+	checkOverlap(dst[:n], a[:n], b[:n])
+
+	p.run(dst, n, func(i, j int) {
+		XOR(dst[i:j], a[i:j], b[i:j])
+	})
+	return n
+}
+
+// XNOR sets each element in according to dst[i] = NOT (a[i] XOR b[i])
+func (p Parallel) XNOR(dst, a, b []byte) int {
+	n := minLen(dst, a, b)
+	checkOverlap(dst[:n], a[:n], b[:n])
+
+	p.run(dst, n, func(i, j int) {
+		XNOR(dst[i:j], a[i:j], b[i:j])
+	})
+	return n
+}
+
+// And sets each element in according to dst[i] = a[i] AND b[i]
+func (p Parallel) And(dst, a, b []byte) int {
+	n := minLen(dst, a, b)
+	checkOverlap(dst[:n], a[:n], b[:n])
+
+	p.run(dst, n, func(i, j int) {
+		And(dst[i:j], a[i:j], b[i:j])
+	})
+	return n
+}
+
+// AndNot sets each element in according to dst[i] = a[i] AND (NOT b[i])
+func (p Parallel) AndNot(dst, a, b []byte) int {
+	n := minLen(dst, a, b)
+	checkOverlap(dst[:n], a[:n], b[:n])
+
+	p.run(dst, n, func(i, j int) {
+		AndNot(dst[i:j], a[i:j], b[i:j])
+	})
+	return n
+}
+
+// NotAnd sets each element in according to dst[i] = NOT (a[i] AND b[i])
+func (p Parallel) NotAnd(dst, a, b []byte) int {
+	n := minLen(dst, a, b)
+	checkOverlap(dst[:n], a[:n], b[:n])
+
+	p.run(dst, n, func(i, j int) {
+		NotAnd(dst[i:j], a[i:j], b[i:j])
+	})
+	return n
+}
+
+// Or sets each element in according to dst[i] = a[i] OR b[i]
+func (p Parallel) Or(dst, a, b []byte) int {
+	n := minLen(dst, a, b)
+	checkOverlap(dst[:n], a[:n], b[:n])
+
+	p.run(dst, n, func(i, j int) {
+		Or(dst[i:j], a[i:j], b[i:j])
+	})
+	return n
+}
+
+// NotOr sets each element in according to dst[i] = NOT (a[i] OR b[i])
+func (p Parallel) NotOr(dst, a, b []byte) int {
+	n := minLen(dst, a, b)
+	checkOverlap(dst[:n], a[:n], b[:n])
+
+	p.run(dst, n, func(i, j int) {
+		NotOr(dst[i:j], a[i:j], b[i:j])
+	})
+	return n
+}
+
+// Not sets each element in according to dst[i] = NOT src[i]
+func (p Parallel) Not(dst, src []byte) int {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	checkOverlap(dst[:n], src[:n], src[:n])
+
+	p.run(dst, n, func(i, j int) {
+		Not(dst[i:j], src[i:j])
+	})
+	return n
+}
@@ -0,0 +1,132 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testParallel(t *testing.T, fn, testFn func(dst, a, b []byte) int) {
+	for _, size := range []int{0, 1, 127, 128, 1000, 4096 + 17, 1<<16 + 3} {
+		for align := 0; align < 3; align++ {
+			p := make([]byte, size+align)[align:]
+			rand.Read(p)
+
+			q := make([]byte, size)
+			rand.Read(q)
+
+			d1 := make([]byte, size+align)[align:]
+			n1 := fn(d1, p, q)
+
+			d2 := make([]byte, size)
+			n2 := testFn(d2, p, q)
+
+			if n1 != n2 {
+				t.Errorf("size = %d, align = %d: expected %d, got %d", size, align, n2, n1)
+			}
+
+			if !bytes.Equal(d1, d2) {
+				t.Errorf("size = %d, align = %d: not equal", size, align)
+			}
+		}
+	}
+}
+
+var testParallelOpts = Parallel{
+	Workers:   5,
+	Threshold: 1,
+}
+
+func TestParallelXOR(t *testing.T) {
+	testParallel(t, testParallelOpts.XOR, testXORBytes)
+}
+
+func TestParallelXNOR(t *testing.T) {
+	testParallel(t, testParallelOpts.XNOR, testXNORBytes)
+}
+
+func TestParallelAnd(t *testing.T) {
+	testParallel(t, testParallelOpts.And, testAndBytes)
+}
+
+func TestParallelAndNot(t *testing.T) {
+	testParallel(t, testParallelOpts.AndNot, testAndNotBytes)
+}
+
+func TestParallelNotAnd(t *testing.T) {
+	testParallel(t, testParallelOpts.NotAnd, testNotAndBytes)
+}
+
+func TestParallelOr(t *testing.T) {
+	testParallel(t, testParallelOpts.Or, testOrBytes)
+}
+
+func TestParallelNotOr(t *testing.T) {
+	testParallel(t, testParallelOpts.NotOr, testNotOrBytes)
+}
+
+func TestParallelNot(t *testing.T) {
+	testParallel(t, func(dst, src, _ []byte) int {
+		return testParallelOpts.Not(dst, src)
+	}, testNotBytes)
+}
+
+func TestParallelWorkers(t *testing.T) {
+	const workers = 4
+
+	buf := make([]byte, 1<<16+cacheLineSize)
+
+	for align := 0; align < cacheLineSize; align++ {
+		var (
+			mu             sync.Mutex
+			active, peak   int
+			calls, covered int
+		)
+
+		Parallel{Workers: workers, Threshold: 1}.run(buf[align:], 1<<16, func(i, j int) {
+			mu.Lock()
+			active++
+			calls++
+			covered += j - i
+			if active > peak {
+				peak = active
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+		})
+
+		if peak > workers || calls > workers {
+			t.Errorf("align = %d: %d calls with %d concurrent, expected at most %d", align, calls, peak, workers)
+		}
+
+		if covered != 1<<16 {
+			t.Errorf("align = %d: covered %d bytes, expected %d", align, covered, 1<<16)
+		}
+	}
+}
+
+func BenchmarkParallelXOR(b *testing.B) {
+	benchmarkThree(b, Parallel{}.XOR)
+}
+
+func BenchmarkParallelAnd(b *testing.B) {
+	benchmarkThree(b, Parallel{}.And)
+}
+
+func BenchmarkParallelNot(b *testing.B) {
+	benchmarkThree(b, func(dst, src, _ []byte) int {
+		return Parallel{}.Not(dst, src)
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import "unsafe"
+
+// The Pointer functions operate on n bytes of memory that is not
+// managed by a Go slice, such as memory returned by mmap or allocated
+// by C. The caller must ensure each pointer is valid for n bytes. They
+// take the same arguments as the assembly kernels and return n.
+
+// bytesAt returns the n bytes at p as a slice.
+func bytesAt(p *byte, n uint64) []byte {
+	if n > uint64(^uint(0)>>1) {
+		panic("bitwise: length out of range")
+	}
+
+	return pointerBytes(unsafe.Pointer(p), int(n))
+}
+
+// XORPointer sets each element in according to dst[i] = a[i] XOR b[i]
+func XORPointer(dst, a, b *byte, n uint64) int {
+	return XOR(bytesAt(dst, n), bytesAt(a, n), bytesAt(b, n))
+}
+
+// XNORPointer sets each element in according to dst[i] = NOT (a[i] XOR b[i])
+func XNORPointer(dst, a, b *byte, n uint64) int {
+	return XNOR(bytesAt(dst, n), bytesAt(a, n), bytesAt(b, n))
+}
+
+// AndPointer sets each element in according to dst[i] = a[i] AND b[i]
+func AndPointer(dst, a, b *byte, n uint64) int {
+	return And(bytesAt(dst, n), bytesAt(a, n), bytesAt(b, n))
+}
+
+// AndNotPointer sets each element in according to dst[i] = a[i] AND (NOT b[i])
+func AndNotPointer(dst, a, b *byte, n uint64) int {
+	return AndNot(bytesAt(dst, n), bytesAt(a, n), bytesAt(b, n))
+}
+
+// NotAndPointer sets each element in according to dst[i] = NOT (a[i] AND b[i])
+func NotAndPointer(dst, a, b *byte, n uint64) int {
+	return NotAnd(bytesAt(dst, n), bytesAt(a, n), bytesAt(b, n))
+}
+
+// OrPointer sets each element in according to dst[i] = a[i] OR b[i]
+func OrPointer(dst, a, b *byte, n uint64) int {
+	return Or(bytesAt(dst, n), bytesAt(a, n), bytesAt(b, n))
+}
+
+// NotOrPointer sets each element in according to dst[i] = NOT (a[i] OR b[i])
+func NotOrPointer(dst, a, b *byte, n uint64) int {
+	return NotOr(bytesAt(dst, n), bytesAt(a, n), bytesAt(b, n))
+}
+
+// NotPointer sets each element in according to dst[i] = NOT src[i]
+func NotPointer(dst, src *byte, n uint64) int {
+	return Not(bytesAt(dst, n), bytesAt(src, n))
+}
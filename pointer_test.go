@@ -0,0 +1,57 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func testPointer(t *testing.T, fn func(dst, a, b *byte, n uint64) int, testFn func(dst, a, b []byte) int) {
+	if err := quick.CheckEqual(func(dst, a, b [100]byte, n uint8) (int, [100]byte) {
+		return testFn(dst[:n%100], a[:], b[:]), dst
+	}, func(dst, a, b [100]byte, n uint8) (int, [100]byte) {
+		return fn(&dst[0], &a[0], &b[0], uint64(n%100)), dst
+	}, &quick.Config{
+		MaxCountScale: 10,
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestPointerOverlap(t *testing.T) {
+	buf := make([]byte, 100)
+	b := make([]byte, 99)
+
+	if !testPanics(func() {
+		XORPointer(&buf[1], &buf[0], &b[0], 99)
+	}) {
+		t.Error("no panic for partial overlap")
+	}
+
+	if !testPanics(func() {
+		NotPointer(&buf[0], &buf[1], 99)
+	}) {
+		t.Error("no panic for partial overlap")
+	}
+}
+
+func TestPointer(t *testing.T) {
+	testPointer(t, XORPointer, testXORBytes)
+	testPointer(t, XNORPointer, testXNORBytes)
+	testPointer(t, AndPointer, testAndBytes)
+	testPointer(t, AndNotPointer, testAndNotBytes)
+	testPointer(t, NotAndPointer, testNotAndBytes)
+	testPointer(t, OrPointer, testOrBytes)
+	testPointer(t, NotOrPointer, testNotOrBytes)
+	testPointer(t, func(dst, src, _ *byte, n uint64) int {
+		return NotPointer(dst, src, n)
+	}, testNotBytes)
+
+	if n := XORPointer(nil, nil, nil, 0); n != 0 {
+		t.Errorf("expected 0, got %d", n)
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import "encoding/binary"
+
+// PrefixXOR sets each bit of dst to the XOR of carry and every bit of
+// src up to and including it. The buffers are treated as bit vectors in
+// LSBFirst order, bit i%8 of byte i/8 is bit i. Only the first
+// min(len(dst), len(src)) bytes are processed.
+//
+// It returns the last bit written to dst, which may be passed as carry
+// to continue the scan over the next part of a longer vector. If no
+// bytes are processed, carry is returned.
+func PrefixXOR(dst, src []byte, carry bool) bool {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return carry
+	}
+
+	checkOverlap(dst[:n], src[:n], src[:n])
+
+	return prefixXOR(dst[:n], src[:n], carry)
+}
+
+// PrefixOR sets each bit of dst to the OR of carry and every bit of src
+// up to and including it. The buffers are treated as bit vectors in
+// LSBFirst order, bit i%8 of byte i/8 is bit i. Only the first
+// min(len(dst), len(src)) bytes are processed.
+//
+// It returns the last bit written to dst, which may be passed as carry
+// to continue the scan over the next part of a longer vector. If no
+// bytes are processed, carry is returned.
+func PrefixOR(dst, src []byte, carry bool) bool {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return carry
+	}
+
+	checkOverlap(dst[:n], src[:n], src[:n])
+
+	dst, src = dst[:n], src[:n]
+
+	// Once a bit has been set every later bit is set, so the carry
+	// is all ones or zero.
+	c := carryMask(carry)
+
+	i := 0
+	for ; i+8 <= len(src); i += 8 {
+		// x | -x sets every bit from the lowest set bit of x.
+		x := binary.LittleEndian.Uint64(src[i:])
+		x |= -x | c
+
+		binary.LittleEndian.PutUint64(dst[i:], x)
+		c = uint64(int64(x) >> 63)
+	}
+
+	for ; i < len(src); i++ {
+		x := src[i]
+		x |= -x | byte(c)
+
+		dst[i] = x
+		c = uint64(int64(int8(x)) >> 7)
+	}
+
+	return c != 0
+}
+
+// carryMask returns all ones if carry is set and zero otherwise.
+func carryMask(carry bool) uint64 {
+	if carry {
+		return ^uint64(0)
+	}
+
+	return 0
+}
+
+func prefixXORGeneric(dst, src []byte, c uint64) uint64 {
+	i := 0
+	for ; i+8 <= len(src); i += 8 {
+		x := binary.LittleEndian.Uint64(src[i:])
+		x ^= x << 1
+		x ^= x << 2
+		x ^= x << 4
+		x ^= x << 8
+		x ^= x << 16
+		x ^= x << 32
+		x ^= c
+
+		binary.LittleEndian.PutUint64(dst[i:], x)
+		c = uint64(int64(x) >> 63)
+	}
+
+	for ; i < len(src); i++ {
+		x := src[i]
+		x ^= x << 1
+		x ^= x << 2
+		x ^= x << 4
+		x ^= byte(c)
+
+		dst[i] = x
+		c = uint64(int64(int8(x)) >> 7)
+	}
+
+	return c
+}
@@ -0,0 +1,25 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+func prefixXOR(dst, src []byte, carry bool) bool {
+	n := len(src)
+	c := carryMask(carry)
+
+	var i int
+	if impl >= implSSE2 && hasPCLMULQDQ && n >= 8 {
+		i = n &^ 7
+		c = prefixXORASM(&dst[0], &src[0], uint64(i), c)
+	}
+
+	return prefixXORGeneric(dst[i:], src[i:], c) != 0
+}
+
+// This function is implemented in bitwise_prefix_amd64.s
+//go:noescape
+func prefixXORASM(dst, src *byte, len, carry uint64) (ret uint64)
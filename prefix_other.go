@@ -0,0 +1,12 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+func prefixXOR(dst, src []byte, carry bool) bool {
+	return prefixXORGeneric(dst, src, carryMask(carry)) != 0
+}
@@ -0,0 +1,115 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func testPrefix(dst, src []byte, carry bool, or bool) bool {
+	for i := 0; i < 8*len(src); i++ {
+		bit := src[i/8]>>uint(i%8)&1 != 0
+		if or {
+			carry = carry || bit
+		} else {
+			carry = carry != bit
+		}
+
+		if carry {
+			dst[i/8] |= 1 << uint(i%8)
+		} else {
+			dst[i/8] &^= 1 << uint(i%8)
+		}
+	}
+
+	return carry
+}
+
+func testPrefixFn(t *testing.T, fn func(dst, src []byte, carry bool) bool, or bool) {
+	testImplementations(t, func(t *testing.T) {
+		for size := 0; size <= 100; size++ {
+			src := make([]byte, size)
+			rand.Read(src)
+
+			// Sparse inputs make prefix OR more interesting.
+			if or && size > 0 {
+				for i := range src {
+					src[i] = 0
+				}
+
+				src[rand.Intn(size)] = 1 << uint(rand.Intn(8))
+			}
+
+			for _, carry := range []bool{false, true} {
+				d1 := make([]byte, size)
+				c1 := fn(d1, src, carry)
+
+				d2 := make([]byte, size)
+				c2 := testPrefix(d2, src, carry, or)
+
+				if c1 != c2 {
+					t.Errorf("size %d with carry %t: expected carry %t, got %t", size, carry, c2, c1)
+				}
+
+				if !bytes.Equal(d1, d2) {
+					t.Errorf("size %d with carry %t: expected %x, got %x", size, carry, d2, d1)
+				}
+			}
+		}
+
+		// Scanning in chunks must match a single scan.
+		src := make([]byte, 1000)
+		rand.Read(src)
+
+		d1 := make([]byte, len(src))
+		fn(d1, src, false)
+
+		d2 := make([]byte, len(src))
+		var carry bool
+		for i := 0; i < len(src); i += 37 {
+			j := i + 37
+			if j > len(src) {
+				j = len(src)
+			}
+
+			carry = fn(d2[i:j], src[i:j], carry)
+		}
+
+		if !bytes.Equal(d1, d2) {
+			t.Error("chunked scan did not match single scan")
+		}
+	})
+}
+
+func TestPrefixXOR(t *testing.T) {
+	testPrefixFn(t, PrefixXOR, false)
+}
+
+func TestPrefixOR(t *testing.T) {
+	testPrefixFn(t, PrefixOR, true)
+}
+
+func benchmarkPrefix(b *testing.B, fn func(dst, src []byte, carry bool) bool) {
+	src := make([]byte, 16*1024)
+	rand.Read(src)
+
+	dst := make([]byte, len(src))
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		fn(dst, src, false)
+	}
+}
+
+func BenchmarkPrefixXOR(b *testing.B) {
+	benchmarkPrefix(b, PrefixXOR)
+}
+
+func BenchmarkPrefixOR(b *testing.B) {
+	benchmarkPrefix(b, PrefixOR)
+}
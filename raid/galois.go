@@ -0,0 +1,36 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package raid
+
+// gfExp and gfLog are the exponent and logarithm tables of GF(2^8) with
+// the polynomial x^8 + x^4 + x^3 + x^2 + 1 and the generator 2. This
+// matches bitwise.GaloisMulXOR.
+var (
+	gfExp [2 * 255]byte
+	gfLog [256]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i], gfExp[i+255] = byte(x), byte(x)
+		gfLog[x] = byte(i)
+
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= 0x11d
+		}
+	}
+}
+
+// gfDiv returns a / b in GF(2^8), b must not be zero.
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
@@ -0,0 +1,198 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// Package raid provides RAID-5 and RAID-6 style parity for erasure coding.
+//
+// A set of shards consists of one or more equally sized data shards
+// followed by the parity shards. With a single XOR parity shard, as
+// used by Encode, any one shard may be lost and recovered from the
+// others. With P and Q parity shards, as used by EncodePQ, any two
+// shards may be lost.
+package raid
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/tmthrgd/go-bitwise"
+)
+
+// blockSize is the number of bytes of each shard processed at a
+// time, it is chosen so that the working set stays in cache.
+const blockSize = 32 * 1024
+
+var (
+	// ErrTooFewShards is returned when there are not enough shards
+	// for at least one data shard and the parity shards.
+	ErrTooFewShards = errors.New("raid: too few shards")
+
+	// ErrTooManyMissing is returned by Reconstruct when more
+	// shards are missing than can be recovered.
+	ErrTooManyMissing = errors.New("raid: too many missing shards to reconstruct")
+)
+
+// ShardSizeError is returned when a shard does not have the same size as
+// the other shards.
+type ShardSizeError struct {
+	Index int
+	Size  int
+	Want  int
+}
+
+func (e *ShardSizeError) Error() string {
+	return fmt.Sprintf("raid: shard %d has size %d, expected %d", e.Index, e.Size, e.Want)
+}
+
+// shardSize returns the size shared by all non-nil shards. A nil
+// shard is treated as missing.
+func shardSize(shards [][]byte) (int, error) {
+	if len(shards) < 2 {
+		return 0, ErrTooFewShards
+	}
+
+	size := -1
+	for i, s := range shards {
+		if s == nil {
+			continue
+		}
+
+		if size < 0 {
+			size = len(s)
+		} else if len(s) != size {
+			return 0, &ShardSizeError{i, len(s), size}
+		}
+	}
+
+	return size, nil
+}
+
+// checkComplete returns an error if any shard is missing.
+func checkComplete(shards [][]byte) error {
+	for i, s := range shards {
+		if s == nil {
+			return fmt.Errorf("raid: shard %d is missing", i)
+		}
+	}
+
+	return nil
+}
+
+// forBlocks calls fn for each blockSize range of a shard.
+func forBlocks(size int, fn func(off, end int)) {
+	for off := 0; off < size; off += blockSize {
+		end := off + blockSize
+		if end > size {
+			end = size
+		}
+
+		fn(off, end)
+	}
+}
+
+// xorShards sets dst to the XOR of the range [off, end) of every shard
+// in srcs.
+func xorShards(dst []byte, srcs [][]byte, off, end int) {
+	if len(srcs) == 1 {
+		copy(dst, srcs[0][off:end])
+		return
+	}
+
+	bitwise.XOR(dst, srcs[0][off:end], srcs[1][off:end])
+
+	for _, s := range srcs[2:] {
+		bitwise.XOR(dst, dst, s[off:end])
+	}
+}
+
+// Encode computes the parity shard, the last element of shards, from
+// the data shards that precede it. The parity shard must already be
+// allocated.
+func Encode(shards [][]byte) error {
+	size, err := shardSize(shards)
+	if err != nil {
+		return err
+	}
+
+	if err := checkComplete(shards); err != nil {
+		return err
+	}
+
+	parity := shards[len(shards)-1]
+	data := shards[:len(shards)-1]
+
+	forBlocks(size, func(off, end int) {
+		xorShards(parity[off:end], data, off, end)
+	})
+	return nil
+}
+
+// Verify reports whether the parity shard, the last element of
+// shards, matches the data shards that precede it.
+func Verify(shards [][]byte) (bool, error) {
+	size, err := shardSize(shards)
+	if err != nil {
+		return false, err
+	}
+
+	if err := checkComplete(shards); err != nil {
+		return false, err
+	}
+
+	parity := shards[len(shards)-1]
+	data := shards[:len(shards)-1]
+
+	buf := make([]byte, blockSize)
+
+	ok := true
+	forBlocks(size, func(off, end int) {
+		if !ok {
+			return
+		}
+
+		xorShards(buf[:end-off], data, off, end)
+		ok = bytes.Equal(buf[:end-off], parity[off:end])
+	})
+	return ok, nil
+}
+
+// Reconstruct recovers a single missing shard, which must be nil, in
+// place. The recovered shard is newly allocated. If no shard is
+// missing, Reconstruct does nothing.
+func Reconstruct(shards [][]byte) error {
+	size, err := shardSize(shards)
+	if err != nil {
+		return err
+	}
+
+	missing := -1
+	for i, s := range shards {
+		if s != nil {
+			continue
+		}
+
+		if missing >= 0 {
+			return ErrTooManyMissing
+		}
+
+		missing = i
+	}
+
+	if missing < 0 {
+		return nil
+	}
+
+	srcs := make([][]byte, 0, len(shards)-1)
+	srcs = append(srcs, shards[:missing]...)
+	srcs = append(srcs, shards[missing+1:]...)
+
+	dst := make([]byte, size)
+	shards[missing] = dst
+
+	forBlocks(size, func(off, end int) {
+		xorShards(dst[off:end], srcs, off, end)
+	})
+	return nil
+}
@@ -0,0 +1,199 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package raid
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/tmthrgd/go-bitwise"
+)
+
+// ErrTooManyShards is returned when more data shards are given than
+// the Q parity shard can distinguish.
+var ErrTooManyShards = errors.New("raid: too many data shards for Q parity, at most 255 are supported")
+
+func checkPQ(shards [][]byte) (int, error) {
+	switch {
+	case len(shards) < 3:
+		return 0, ErrTooFewShards
+	case len(shards)-2 > 255:
+		return 0, ErrTooManyShards
+	}
+
+	return shardSize(shards)
+}
+
+// syndromes sets p and q to the P and Q syndromes of the range
+// [off, end) of the data shards, skipping the shards at index x and y.
+func syndromes(p, q []byte, data [][]byte, off, end, x, y int) {
+	for i := range p {
+		p[i] = 0
+	}
+	for i := range q {
+		q[i] = 0
+	}
+
+	for i, d := range data {
+		if i == x || i == y {
+			continue
+		}
+
+		bitwise.XOR(p, p, d[off:end])
+		bitwise.GaloisMulXOR(q, d[off:end], gfExp[i])
+	}
+}
+
+// EncodePQ computes the P and Q parity shards, the last two elements
+// of shards, from the data shards that precede them. P is the XOR of
+// the data shards and Q is their Reed–Solomon syndrome over GF(2^8),
+// together they allow any two shards to be reconstructed. The parity
+// shards must already be allocated.
+func EncodePQ(shards [][]byte) error {
+	size, err := checkPQ(shards)
+	if err != nil {
+		return err
+	}
+
+	if err := checkComplete(shards); err != nil {
+		return err
+	}
+
+	data := shards[:len(shards)-2]
+	p, q := shards[len(shards)-2], shards[len(shards)-1]
+
+	forBlocks(size, func(off, end int) {
+		syndromes(p[off:end], q[off:end], data, off, end, -1, -1)
+	})
+	return nil
+}
+
+// VerifyPQ reports whether the P and Q parity shards, the last two
+// elements of shards, match the data shards that precede them.
+func VerifyPQ(shards [][]byte) (bool, error) {
+	size, err := checkPQ(shards)
+	if err != nil {
+		return false, err
+	}
+
+	if err := checkComplete(shards); err != nil {
+		return false, err
+	}
+
+	data := shards[:len(shards)-2]
+	p, q := shards[len(shards)-2], shards[len(shards)-1]
+
+	pb, qb := make([]byte, blockSize), make([]byte, blockSize)
+
+	ok := true
+	forBlocks(size, func(off, end int) {
+		if !ok {
+			return
+		}
+
+		syndromes(pb[:end-off], qb[:end-off], data, off, end, -1, -1)
+		ok = bytes.Equal(pb[:end-off], p[off:end]) && bytes.Equal(qb[:end-off], q[off:end])
+	})
+	return ok, nil
+}
+
+// ReconstructPQ recovers up to two missing shards, which must be nil,
+// in place. The recovered shards are newly allocated. If no shard is
+// missing, ReconstructPQ does nothing.
+func ReconstructPQ(shards [][]byte) error {
+	size, err := checkPQ(shards)
+	if err != nil {
+		return err
+	}
+
+	nd := len(shards) - 2
+	dx, dy := -1, -1
+	pMissing, qMissing := shards[nd] == nil, shards[nd+1] == nil
+
+	var missing int
+	for i, s := range shards {
+		if s != nil {
+			continue
+		}
+
+		if missing++; missing > 2 {
+			return ErrTooManyMissing
+		}
+
+		switch {
+		case i >= nd:
+		case dx < 0:
+			dx = i
+		default:
+			dy = i
+		}
+	}
+
+	if missing == 0 {
+		return nil
+	}
+
+	for i, s := range shards {
+		if s == nil {
+			shards[i] = make([]byte, size)
+		}
+	}
+
+	data := shards[:nd]
+	p, q := shards[nd], shards[nd+1]
+
+	// a and b are the coefficients used to recover data shard dx when
+	// both dx and dy are missing:
+	//  Dx = a * (P + Pxy) + b * (Q + Qxy)
+	// where Pxy and Qxy are the syndromes of the remaining data shards.
+	var a, b byte
+	if dy >= 0 {
+		gyx := gfExp[dy-dx]
+		a = gfDiv(gyx, gyx^1)
+		b = gfDiv(gfExp[255-dx], gyx^1)
+	}
+
+	pb, qb := make([]byte, blockSize), make([]byte, blockSize)
+
+	forBlocks(size, func(off, end int) {
+		pb, qb := pb[:end-off], qb[:end-off]
+		syndromes(pb, qb, data, off, end, dx, dy)
+
+		switch {
+		case dy >= 0:
+			bitwise.XOR(pb, pb, p[off:end])
+			bitwise.XOR(qb, qb, q[off:end])
+
+			x, y := data[dx][off:end], data[dy][off:end]
+			bitwise.GaloisMulXOR(x, pb, a)
+			bitwise.GaloisMulXOR(x, qb, b)
+			bitwise.XOR(y, pb, x)
+		case dx >= 0 && !pMissing:
+			x := data[dx][off:end]
+			bitwise.XOR(x, pb, p[off:end])
+
+			if qMissing {
+				bitwise.GaloisMulXOR(qb, x, gfExp[dx])
+				copy(q[off:end], qb)
+			}
+		case dx >= 0:
+			bitwise.XOR(qb, qb, q[off:end])
+
+			x := data[dx][off:end]
+			bitwise.GaloisMulXOR(x, qb, gfExp[255-dx])
+			bitwise.XOR(p[off:end], pb, x)
+		default:
+			if pMissing {
+				copy(p[off:end], pb)
+			}
+
+			if qMissing {
+				copy(q[off:end], qb)
+			}
+		}
+	})
+	return nil
+}
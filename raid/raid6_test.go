@@ -0,0 +1,118 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package raid
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func TestReconstructPQ(t *testing.T) {
+	if err := quick.Check(func(seed int64, n uint8, size uint16, drop1, drop2 uint8) bool {
+		r := rand.New(rand.NewSource(seed))
+
+		shards := newShards(r, int(n)%16+3, int(size)%(3*blockSize))
+		if err := EncodePQ(shards); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if ok, err := VerifyPQ(shards); !ok || err != nil {
+			t.Log("VerifyPQ failed after EncodePQ", err)
+			return false
+		}
+
+		orig := append([][]byte(nil), shards...)
+
+		shards[int(drop1)%len(shards)] = nil
+		shards[int(drop2)%len(shards)] = nil
+
+		if err := ReconstructPQ(shards); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		for i := range shards {
+			if !bytes.Equal(orig[i], shards[i]) {
+				t.Logf("shard %d not reconstructed", i)
+				return false
+			}
+		}
+
+		return true
+	}, &quick.Config{
+		MaxCountScale: 5,
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReconstructPQAll(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	shards := newShards(r, 8, 100)
+	if err := EncodePQ(shards); err != nil {
+		t.Fatal(err)
+	}
+
+	for x := range shards {
+		for y := x; y < len(shards); y++ {
+			s := append([][]byte(nil), shards...)
+			s[x], s[y] = nil, nil
+
+			if err := ReconstructPQ(s); err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(s[x], shards[x]) || !bytes.Equal(s[y], shards[y]) {
+				t.Errorf("failed to reconstruct shards %d and %d", x, y)
+			}
+		}
+	}
+}
+
+func TestVerifyPQCorrupt(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	shards := newShards(r, 6, 2*blockSize+13)
+	if err := EncodePQ(shards); err != nil {
+		t.Fatal(err)
+	}
+
+	shards[5][blockSize+7] ^= 0x10
+
+	if ok, err := VerifyPQ(shards); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Error("VerifyPQ succeeded for corrupt shards")
+	}
+}
+
+func TestErrorsPQ(t *testing.T) {
+	if err := EncodePQ([][]byte{make([]byte, 4), make([]byte, 4)}); err != ErrTooFewShards {
+		t.Errorf("expected ErrTooFewShards, got %v", err)
+	}
+
+	if err := EncodePQ(make([][]byte, 258)); err != ErrTooManyShards {
+		t.Errorf("expected ErrTooManyShards, got %v", err)
+	}
+
+	if err := ReconstructPQ([][]byte{nil, make([]byte, 4), nil, nil}); err != ErrTooManyMissing {
+		t.Errorf("expected ErrTooManyMissing, got %v", err)
+	}
+}
+
+func BenchmarkEncodePQ(b *testing.B) {
+	shards := newShards(rand.New(rand.NewSource(1)), 10, 1024*1024)
+
+	b.SetBytes(int64(8 * len(shards[0])))
+
+	for i := 0; i < b.N; i++ {
+		EncodePQ(shards)
+	}
+}
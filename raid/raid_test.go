@@ -0,0 +1,103 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package raid
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"testing/quick"
+)
+
+func newShards(r *rand.Rand, n, size int) [][]byte {
+	shards := make([][]byte, n)
+	for i := range shards {
+		shards[i] = make([]byte, size)
+		r.Read(shards[i])
+	}
+
+	return shards
+}
+
+func TestReconstruct(t *testing.T) {
+	if err := quick.Check(func(seed int64, n uint8, size uint16, drop uint8) bool {
+		r := rand.New(rand.NewSource(seed))
+
+		shards := newShards(r, int(n)%16+2, int(size)%(3*blockSize))
+		if err := Encode(shards); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		if ok, err := Verify(shards); !ok || err != nil {
+			t.Log("Verify failed after Encode", err)
+			return false
+		}
+
+		lost := int(drop) % len(shards)
+		orig := shards[lost]
+		shards[lost] = nil
+
+		if err := Reconstruct(shards); err != nil {
+			t.Log(err)
+			return false
+		}
+
+		return bytes.Equal(orig, shards[lost])
+	}, &quick.Config{
+		MaxCountScale: 5,
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestVerifyCorrupt(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	shards := newShards(r, 5, 2*blockSize+13)
+	if err := Encode(shards); err != nil {
+		t.Fatal(err)
+	}
+
+	shards[2][blockSize+7] ^= 0x10
+
+	if ok, err := Verify(shards); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Error("Verify succeeded for corrupt shards")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	if err := Encode([][]byte{make([]byte, 4)}); err != ErrTooFewShards {
+		t.Errorf("expected ErrTooFewShards, got %v", err)
+	}
+
+	err := Encode([][]byte{make([]byte, 4), make([]byte, 4), make([]byte, 3)})
+	if serr, ok := err.(*ShardSizeError); !ok {
+		t.Errorf("expected *ShardSizeError, got %v", err)
+	} else if *serr != (ShardSizeError{2, 3, 4}) {
+		t.Errorf("wrong ShardSizeError, got %#v", serr)
+	}
+
+	if err := Encode([][]byte{make([]byte, 4), nil, make([]byte, 4)}); err == nil {
+		t.Error("Encode succeeded with missing shard")
+	}
+
+	if err := Reconstruct([][]byte{make([]byte, 4), nil, nil}); err != ErrTooManyMissing {
+		t.Errorf("expected ErrTooManyMissing, got %v", err)
+	}
+}
+
+func BenchmarkEncode(b *testing.B) {
+	shards := newShards(rand.New(rand.NewSource(1)), 9, 1024*1024)
+
+	b.SetBytes(int64(8 * len(shards[0])))
+
+	for i := 0; i < b.N; i++ {
+		Encode(shards)
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// Package strict provides variants of the bitwise operations that
+// require every slice to have the same length.
+//
+// The operations in the bitwise package silently process the shortest
+// of their arguments. The functions in this package instead return a
+// *LengthError, or panic with one for the Must variants, without
+// modifying dst.
+package strict
+
+import (
+	"fmt"
+
+	"github.com/tmthrgd/go-bitwise"
+)
+
+// LengthError is returned when the arguments of an operation do not all
+// have the same length. It describes the first argument that is shorter
+// than the longest.
+type LengthError struct {
+	Op   string
+	Arg  string
+	Len  int
+	Want int
+}
+
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("bitwise/strict: %s: %s has length %d, expected %d", e.Op, e.Arg, e.Len, e.Want)
+}
+
+var (
+	unaryArgs  = [...]string{"dst", "src"}
+	binaryArgs = [...]string{"dst", "a", "b"}
+)
+
+// Check returns a *LengthError if dst and srcs do not all have the same
+// length. op names the operation in the error. It is intended for use
+// in tests that call the lenient bitwise functions.
+func Check(op string, dst []byte, srcs ...[]byte) error {
+	want := len(dst)
+	for _, s := range srcs {
+		if len(s) > want {
+			want = len(s)
+		}
+	}
+
+	names := binaryArgs[:]
+	if len(srcs) == 1 {
+		names = unaryArgs[:]
+	}
+
+	for i := -1; i < len(srcs); i++ {
+		b := dst
+		if i >= 0 {
+			b = srcs[i]
+		}
+
+		if len(b) == want {
+			continue
+		}
+
+		name := fmt.Sprintf("argument %d", i+2)
+		if i+1 < len(names) {
+			name = names[i+1]
+		}
+
+		return &LengthError{op, name, len(b), want}
+	}
+
+	return nil
+}
+
+// XOR sets each element in according to dst[i] = a[i] XOR b[i]
+func XOR(dst, a, b []byte) error {
+	if err := Check("XOR", dst, a, b); err != nil {
+		return err
+	}
+
+	bitwise.XOR(dst, a, b)
+	return nil
+}
+
+// XNOR sets each element in according to dst[i] = NOT (a[i] XOR b[i])
+func XNOR(dst, a, b []byte) error {
+	if err := Check("XNOR", dst, a, b); err != nil {
+		return err
+	}
+
+	bitwise.XNOR(dst, a, b)
+	return nil
+}
+
+// And sets each element in according to dst[i] = a[i] AND b[i]
+func And(dst, a, b []byte) error {
+	if err := Check("And", dst, a, b); err != nil {
+		return err
+	}
+
+	bitwise.And(dst, a, b)
+	return nil
+}
+
+// AndNot sets each element in according to dst[i] = a[i] AND (NOT b[i])
+func AndNot(dst, a, b []byte) error {
+	if err := Check("AndNot", dst, a, b); err != nil {
+		return err
+	}
+
+	bitwise.AndNot(dst, a, b)
+	return nil
+}
+
+// NotAnd sets each element in according to dst[i] = NOT (a[i] AND b[i])
+func NotAnd(dst, a, b []byte) error {
+	if err := Check("NotAnd", dst, a, b); err != nil {
+		return err
+	}
+
+	bitwise.NotAnd(dst, a, b)
+	return nil
+}
+
+// Or sets each element in according to dst[i] = a[i] OR b[i]
+func Or(dst, a, b []byte) error {
+	if err := Check("Or", dst, a, b); err != nil {
+		return err
+	}
+
+	bitwise.Or(dst, a, b)
+	return nil
+}
+
+// NotOr sets each element in according to dst[i] = NOT (a[i] OR b[i])
+func NotOr(dst, a, b []byte) error {
+	if err := Check("NotOr", dst, a, b); err != nil {
+		return err
+	}
+
+	bitwise.NotOr(dst, a, b)
+	return nil
+}
+
+// Not sets each element in according to dst[i] = NOT src[i]
+func Not(dst, src []byte) error {
+	if err := Check("Not", dst, src); err != nil {
+		return err
+	}
+
+	bitwise.Not(dst, src)
+	return nil
+}
+
+func must(err error) {
+	if err != nil {
+		panic(err)
+	}
+}
+
+// MustXOR is like XOR but panics if the lengths differ.
+func MustXOR(dst, a, b []byte) {
+	must(XOR(dst, a, b))
+}
+
+// MustXNOR is like XNOR but panics if the lengths differ.
+func MustXNOR(dst, a, b []byte) {
+	must(XNOR(dst, a, b))
+}
+
+// MustAnd is like And but panics if the lengths differ.
+func MustAnd(dst, a, b []byte) {
+	must(And(dst, a, b))
+}
+
+// MustAndNot is like AndNot but panics if the lengths differ.
+func MustAndNot(dst, a, b []byte) {
+	must(AndNot(dst, a, b))
+}
+
+// MustNotAnd is like NotAnd but panics if the lengths differ.
+func MustNotAnd(dst, a, b []byte) {
+	must(NotAnd(dst, a, b))
+}
+
+// MustOr is like Or but panics if the lengths differ.
+func MustOr(dst, a, b []byte) {
+	must(Or(dst, a, b))
+}
+
+// MustNotOr is like NotOr but panics if the lengths differ.
+func MustNotOr(dst, a, b []byte) {
+	must(NotOr(dst, a, b))
+}
+
+// MustNot is like Not but panics if the lengths differ.
+func MustNot(dst, src []byte) {
+	must(Not(dst, src))
+}
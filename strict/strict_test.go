@@ -0,0 +1,72 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package strict
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestXOR(t *testing.T) {
+	dst := make([]byte, 4)
+	if err := XOR(dst, []byte{1, 2, 3, 4}, []byte{4, 3, 2, 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(dst, []byte{5, 1, 1, 5}) {
+		t.Errorf("wrong result %x", dst)
+	}
+}
+
+func TestLengthError(t *testing.T) {
+	for _, tc := range []struct {
+		dst, a, b []byte
+		want      LengthError
+	}{
+		{make([]byte, 3), make([]byte, 4), make([]byte, 4), LengthError{"XOR", "dst", 3, 4}},
+		{make([]byte, 4), make([]byte, 2), make([]byte, 4), LengthError{"XOR", "a", 2, 4}},
+		{make([]byte, 4), make([]byte, 4), nil, LengthError{"XOR", "b", 0, 4}},
+	} {
+		dst := append([]byte{}, tc.dst...)
+
+		err := XOR(tc.dst, tc.a, tc.b)
+		if lerr, ok := err.(*LengthError); !ok {
+			t.Errorf("expected *LengthError, got %v", err)
+		} else if *lerr != tc.want {
+			t.Errorf("expected %#v, got %#v", tc.want, *lerr)
+		}
+
+		if !bytes.Equal(dst, tc.dst) {
+			t.Error("dst was modified")
+		}
+	}
+
+	err := Not(make([]byte, 4), make([]byte, 5))
+	if lerr, ok := err.(*LengthError); !ok || *lerr != (LengthError{"Not", "dst", 4, 5}) {
+		t.Errorf("wrong error for Not, got %v", err)
+	}
+}
+
+func TestMust(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(*LengthError); !ok {
+			t.Error("expected panic with *LengthError")
+		}
+	}()
+
+	MustAnd(make([]byte, 4), make([]byte, 4), make([]byte, 3))
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check("Or", make([]byte, 7), make([]byte, 7), make([]byte, 7)); err != nil {
+		t.Error(err)
+	}
+
+	err := Check("Op", make([]byte, 2), make([]byte, 2), make([]byte, 2), make([]byte, 1))
+	if lerr, ok := err.(*LengthError); !ok || lerr.Arg != "argument 4" {
+		t.Errorf("wrong error, got %v", err)
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+// XORString sets each element in according to dst[i] = a[i] XOR b[i]
+func XORString(dst, a []byte, b string) int {
+	return XOR(dst, a, stringBytes(b))
+}
+
+// XNORString sets each element in according to dst[i] = NOT (a[i] XOR b[i])
+func XNORString(dst, a []byte, b string) int {
+	return XNOR(dst, a, stringBytes(b))
+}
+
+// AndString sets each element in according to dst[i] = a[i] AND b[i]
+func AndString(dst, a []byte, b string) int {
+	return And(dst, a, stringBytes(b))
+}
+
+// AndNotString sets each element in according to dst[i] = a[i] AND (NOT b[i])
+func AndNotString(dst, a []byte, b string) int {
+	return AndNot(dst, a, stringBytes(b))
+}
+
+// NotAndString sets each element in according to dst[i] = NOT (a[i] AND b[i])
+func NotAndString(dst, a []byte, b string) int {
+	return NotAnd(dst, a, stringBytes(b))
+}
+
+// OrString sets each element in according to dst[i] = a[i] OR b[i]
+func OrString(dst, a []byte, b string) int {
+	return Or(dst, a, stringBytes(b))
+}
+
+// NotOrString sets each element in according to dst[i] = NOT (a[i] OR b[i])
+func NotOrString(dst, a []byte, b string) int {
+	return NotOr(dst, a, stringBytes(b))
+}
+
+// NotString sets each element in according to dst[i] = NOT src[i]
+func NotString(dst []byte, src string) int {
+	return Not(dst, stringBytes(src))
+}
@@ -0,0 +1,39 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func testString(t *testing.T, fn func(dst, a []byte, b string) int, testFn func(dst, a, b []byte) int) {
+	if err := quick.CheckEqual(func(dst, a []byte, b string) (int, []byte) {
+		d := append([]byte{}, dst...)
+		return testFn(d, a, []byte(b)), d
+	}, func(dst, a []byte, b string) (int, []byte) {
+		return fn(dst, a, b), dst
+	}, &quick.Config{
+		MaxCountScale: 10,
+	}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestString(t *testing.T) {
+	testString(t, XORString, testXORBytes)
+	testString(t, XNORString, testXNORBytes)
+	testString(t, AndString, testAndBytes)
+	testString(t, AndNotString, testAndNotBytes)
+	testString(t, NotAndString, testNotAndBytes)
+	testString(t, OrString, testOrBytes)
+	testString(t, NotOrString, testNotOrBytes)
+	testString(t, func(dst, _ []byte, src string) int {
+		return NotString(dst, src)
+	}, func(dst, _, src []byte) int {
+		return testNotBytes(dst, src, nil)
+	})
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+// Translate sets each element in according to dst[i] = table[src[i]]. It
+// returns the number of bytes translated, the smaller of len(dst) and
+// len(src).
+func Translate(dst, src []byte, table *[256]byte) int {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst[:n], src[:n], src[:n])
+
+	translate(dst[:n], src[:n], table)
+	return n
+}
+
+// Lookup16 sets each element in according to
+// dst[i] = lo[src[i] & 0x0f] XOR hi[src[i] >> 4]. It returns the number
+// of bytes set, the smaller of len(dst) and len(src).
+//
+// Lookup16 is faster than Translate but only applies maps that can be
+// split into a lookup of each nibble. These include any map that
+// depends on only one nibble, with the other table zero, and any map
+// that is linear over GF(2), such as bit permutations and
+// multiplication in GF(2^8), where lo and hi hold the map of each
+// nibble value shifted into place.
+func Lookup16(dst, src []byte, lo, hi *[16]byte) int {
+	n := len(src)
+	if len(dst) < n {
+		n = len(dst)
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	checkOverlap(dst[:n], src[:n], src[:n])
+
+	lookup16(dst[:n], src[:n], lo, hi)
+	return n
+}
+
+func translateGeneric(dst, src []byte, table *[256]byte) {
+	for i, c := range src {
+		dst[i] = table[c]
+	}
+}
+
+func lookup16Generic(dst, src []byte, lo, hi *[16]byte) {
+	for i, c := range src {
+		dst[i] = lo[c&0x0f] ^ hi[c>>4]
+	}
+}
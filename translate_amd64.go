@@ -0,0 +1,56 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build amd64,!gccgo,!appengine,!purego
+
+package bitwise
+
+func translate(dst, src []byte, table *[256]byte) {
+	n := len(src)
+
+	var i int
+	switch {
+	case impl >= implAVX2 && n >= 32:
+		i = n &^ 31
+		translateAVX2(&dst[0], &src[0], uint64(i), table)
+	case impl >= implSSE2 && hasSSSE3 && n >= 16:
+		i = n &^ 15
+		translateASM(&dst[0], &src[0], uint64(i), table)
+	}
+
+	translateGeneric(dst[i:], src[i:], table)
+}
+
+func lookup16(dst, src []byte, lo, hi *[16]byte) {
+	n := len(src)
+
+	var i int
+	switch {
+	case impl >= implAVX2 && n >= 32:
+		i = n &^ 31
+		lookup16AVX2(&dst[0], &src[0], uint64(i), lo, hi)
+	case impl >= implSSE2 && hasSSSE3 && n >= 16:
+		i = n &^ 15
+		lookup16ASM(&dst[0], &src[0], uint64(i), lo, hi)
+	}
+
+	lookup16Generic(dst[i:], src[i:], lo, hi)
+}
+
+// This function is implemented in bitwise_translate_amd64.s
+//go:noescape
+func translateASM(dst, src *byte, len uint64, table *[256]byte)
+
+// This function is implemented in bitwise_translate_amd64.s
+//go:noescape
+func translateAVX2(dst, src *byte, len uint64, table *[256]byte)
+
+// This function is implemented in bitwise_translate_amd64.s
+//go:noescape
+func lookup16ASM(dst, src *byte, len uint64, lo, hi *[16]byte)
+
+// This function is implemented in bitwise_translate_amd64.s
+//go:noescape
+func lookup16AVX2(dst, src *byte, len uint64, lo, hi *[16]byte)
@@ -0,0 +1,16 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !amd64 gccgo appengine purego
+
+package bitwise
+
+func translate(dst, src []byte, table *[256]byte) {
+	translateGeneric(dst, src, table)
+}
+
+func lookup16(dst, src []byte, lo, hi *[16]byte) {
+	lookup16Generic(dst, src, lo, hi)
+}
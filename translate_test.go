@@ -0,0 +1,147 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+package bitwise
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestTranslate(t *testing.T) {
+	var table [256]byte
+	rand.Read(table[:])
+
+	testImplementations(t, func(t *testing.T) {
+		for size := 0; size <= 300; size++ {
+			src := make([]byte, size)
+			rand.Read(src)
+
+			d1 := make([]byte, size)
+			if n := Translate(d1, src, &table); n != size {
+				t.Errorf("size %d: expected %d bytes, got %d", size, size, n)
+			}
+
+			d2 := make([]byte, size)
+			for i, c := range src {
+				d2[i] = table[c]
+			}
+
+			if !bytes.Equal(d1, d2) {
+				t.Errorf("size %d: expected %x, got %x", size, d2, d1)
+			}
+
+			Translate(src, src, &table)
+
+			if !bytes.Equal(src, d2) {
+				t.Errorf("size %d: in place translation failed", size)
+			}
+		}
+	})
+}
+
+func TestTranslateAllBytes(t *testing.T) {
+	var table [256]byte
+	for i := range table {
+		table[i] = byte(255 - i)
+	}
+
+	testImplementations(t, func(t *testing.T) {
+		src := make([]byte, 256)
+		for i := range src {
+			src[i] = byte(i)
+		}
+
+		dst := make([]byte, 256)
+		Translate(dst, src, &table)
+
+		if !bytes.Equal(dst, table[:]) {
+			t.Errorf("expected %x, got %x", table[:], dst)
+		}
+	})
+}
+
+func TestLookup16(t *testing.T) {
+	var lo, hi [16]byte
+	rand.Read(lo[:])
+	rand.Read(hi[:])
+
+	testImplementations(t, func(t *testing.T) {
+		for size := 0; size <= 300; size++ {
+			src := make([]byte, size)
+			rand.Read(src)
+
+			d1 := make([]byte, size)
+			if n := Lookup16(d1, src, &lo, &hi); n != size {
+				t.Errorf("size %d: expected %d bytes, got %d", size, size, n)
+			}
+
+			d2 := make([]byte, size)
+			for i, c := range src {
+				d2[i] = lo[c%16] ^ hi[c/16]
+			}
+
+			if !bytes.Equal(d1, d2) {
+				t.Errorf("size %d: expected %x, got %x", size, d2, d1)
+			}
+		}
+	})
+}
+
+func TestLookup16Galois(t *testing.T) {
+	const c = 0x1d
+
+	var tables [32]byte
+	galoisTables(&tables, c)
+
+	var lo, hi [16]byte
+	copy(lo[:], tables[:16])
+	copy(hi[:], tables[16:])
+
+	src := make([]byte, 1000)
+	rand.Read(src)
+
+	d1 := make([]byte, len(src))
+	Lookup16(d1, src, &lo, &hi)
+
+	d2 := make([]byte, len(src))
+	GaloisMulXOR(d2, src, c)
+
+	if !bytes.Equal(d1, d2) {
+		t.Error("Lookup16 with galois tables did not match GaloisMulXOR")
+	}
+}
+
+func BenchmarkTranslate(b *testing.B) {
+	var table [256]byte
+	rand.Read(table[:])
+
+	src := make([]byte, 16*1024)
+	rand.Read(src)
+
+	dst := make([]byte, len(src))
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		Translate(dst, src, &table)
+	}
+}
+
+func BenchmarkLookup16(b *testing.B) {
+	var lo, hi [16]byte
+	rand.Read(lo[:])
+	rand.Read(hi[:])
+
+	src := make([]byte, 16*1024)
+	rand.Read(src)
+
+	dst := make([]byte, len(src))
+	b.SetBytes(int64(len(src)))
+
+	for i := 0; i < b.N; i++ {
+		Lookup16(dst, src, &lo, &hi)
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build !go1.20
+
+package bitwise
+
+import "unsafe"
+
+// pointerBytes returns the n bytes at p as a slice. unsafe.Slice is not
+// available, so the slice header is built by hand.
+func pointerBytes(p unsafe.Pointer, n int) []byte {
+	if n <= 0 {
+		return nil
+	}
+
+	return *(*[]byte)(unsafe.Pointer(&struct {
+		data     unsafe.Pointer
+		len, cap int
+	}{p, n, n}))
+}
+
+// stringBytes returns the bytes of s without copying them. The result
+// must never be written to.
+func stringBytes(s string) []byte {
+	return *(*[]byte)(unsafe.Pointer(&struct {
+		string
+		cap int
+	}{s, len(s)}))
+}
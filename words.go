@@ -0,0 +1,78 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build go1.18
+
+package bitwise
+
+import "unsafe"
+
+// Word is the set of element types accepted by the Words functions.
+type Word interface {
+	~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uint | ~uintptr
+}
+
+// asBytes returns the memory of s as a byte slice.
+func asBytes[T Word](s []T) []byte {
+	if len(s) == 0 {
+		return nil
+	}
+
+	return unsafe.Slice((*byte)(unsafe.Pointer(&s[0])), len(s)*int(unsafe.Sizeof(s[0])))
+}
+
+// elems converts n, a number of bytes, into a number of elements of T.
+func elems[T Word](n int) int {
+	var x T
+	return n / int(unsafe.Sizeof(x))
+}
+
+// XORWords sets each element in according to dst[i] = a[i] XOR b[i]. It
+// returns the number of elements written.
+func XORWords[T Word](dst, a, b []T) int {
+	return elems[T](XOR(asBytes(dst), asBytes(a), asBytes(b)))
+}
+
+// XNORWords sets each element in according to dst[i] = NOT (a[i] XOR b[i]).
+// It returns the number of elements written.
+func XNORWords[T Word](dst, a, b []T) int {
+	return elems[T](XNOR(asBytes(dst), asBytes(a), asBytes(b)))
+}
+
+// AndWords sets each element in according to dst[i] = a[i] AND b[i]. It
+// returns the number of elements written.
+func AndWords[T Word](dst, a, b []T) int {
+	return elems[T](And(asBytes(dst), asBytes(a), asBytes(b)))
+}
+
+// AndNotWords sets each element in according to dst[i] = a[i] AND (NOT b[i]).
+// It returns the number of elements written.
+func AndNotWords[T Word](dst, a, b []T) int {
+	return elems[T](AndNot(asBytes(dst), asBytes(a), asBytes(b)))
+}
+
+// NotAndWords sets each element in according to dst[i] = NOT (a[i] AND b[i]).
+// It returns the number of elements written.
+func NotAndWords[T Word](dst, a, b []T) int {
+	return elems[T](NotAnd(asBytes(dst), asBytes(a), asBytes(b)))
+}
+
+// OrWords sets each element in according to dst[i] = a[i] OR b[i]. It
+// returns the number of elements written.
+func OrWords[T Word](dst, a, b []T) int {
+	return elems[T](Or(asBytes(dst), asBytes(a), asBytes(b)))
+}
+
+// NotOrWords sets each element in according to dst[i] = NOT (a[i] OR b[i]).
+// It returns the number of elements written.
+func NotOrWords[T Word](dst, a, b []T) int {
+	return elems[T](NotOr(asBytes(dst), asBytes(a), asBytes(b)))
+}
+
+// NotWords sets each element in according to dst[i] = NOT src[i]. It
+// returns the number of elements written.
+func NotWords[T Word](dst, src []T) int {
+	return elems[T](Not(asBytes(dst), asBytes(src)))
+}
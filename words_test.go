@@ -0,0 +1,68 @@
+// Copyright 2017 Tom Thorogood. All rights reserved.
+// Use of this source code is governed by a
+// Modified BSD License license that can be found in
+// the LICENSE file.
+
+// +build go1.18
+
+package bitwise
+
+import (
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+type bitmapWord uint64
+
+func testWords[T Word](t *testing.T, fn func(dst, a, b []T) int, op func(a, b T) T) {
+	if err := quick.CheckEqual(func(dst, a, b []T) (int, []T) {
+		n := len(a)
+		if len(b) < n {
+			n = len(b)
+		}
+		if len(dst) < n {
+			n = len(dst)
+		}
+
+		d := append([]T{}, dst...)
+		for i := 0; i < n; i++ {
+			d[i] = op(a[i], b[i])
+		}
+
+		return n, d
+	}, func(dst, a, b []T) (int, []T) {
+		return fn(dst, a, b), dst
+	}, &quick.Config{
+		MaxCountScale: 10,
+	}); err != nil {
+		t.Errorf("%v: %v", reflect.TypeOf(op).In(0), err)
+	}
+}
+
+func testWordOps[T Word](t *testing.T) {
+	testWords(t, XORWords[T], func(a, b T) T { return a ^ b })
+	testWords(t, XNORWords[T], func(a, b T) T { return ^(a ^ b) })
+	testWords(t, AndWords[T], func(a, b T) T { return a & b })
+	testWords(t, AndNotWords[T], func(a, b T) T { return a &^ b })
+	testWords(t, NotAndWords[T], func(a, b T) T { return ^(a & b) })
+	testWords(t, OrWords[T], func(a, b T) T { return a | b })
+	testWords(t, NotOrWords[T], func(a, b T) T { return ^(a | b) })
+	testWords(t, func(dst, src, b []T) int {
+		if len(b) < len(src) {
+			src = src[:len(b)]
+		}
+
+		return NotWords(dst, src)
+	}, func(a, _ T) T { return ^a })
+}
+
+func TestWords(t *testing.T) {
+	testWordOps[uint8](t)
+	testWordOps[uint16](t)
+	testWordOps[uint32](t)
+	testWordOps[uint64](t)
+	testWordOps[uint](t)
+	testWordOps[uintptr](t)
+	testWordOps[bitmapWord](t)
+}